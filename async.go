@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/sirupsen/logrus"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy decides what an asynchronous output does with an entry
+// when its buffer is full.
+type OverflowPolicy int32
+
+const (
+	// OverflowBlock waits for room in the buffer.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest buffered entry.
+	OverflowDropOldest
+	// OverflowDrop drops the new entry.
+	OverflowDrop
+)
+
+const exitFlushTimeout = 5 * time.Second
+
+var (
+	asyncOut       *asyncWriter
+	overflowPolicy atomic.Int32
+)
+
+// SetAsync makes the package logger write through a buffer of bufSize
+// entries drained by a background goroutine, so logging does not block on a
+// slow output. A bufSize of zero or less flushes the buffer and switches
+// back to synchronous writes. Buffered entries are flushed before a Fatal
+// exits or a Panic panics; call Flush before returning from main.
+func SetAsync(bufSize int) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	old := asyncOut
+	if bufSize > 0 {
+		asyncOut = newAsyncWriter(conf.Output, bufSize)
+		log.SetOutput(&errorWriter{w: asyncOut, pkg: true})
+	} else {
+		asyncOut = nil
+		log.SetOutput(&errorWriter{w: conf.Output, pkg: true})
+	}
+	if old != nil {
+		old.close()
+	}
+}
+
+// SetOverflowPolicy sets what the asynchronous output does when its buffer
+// is full. The default is OverflowBlock.
+func SetOverflowPolicy(policy OverflowPolicy) {
+	overflowPolicy.Store(int32(policy))
+}
+
+// Flush waits until the entries buffered by SetAsync at the time of the call
+// are written, or ctx is done.
+func Flush(ctx context.Context) error {
+	mu.Lock()
+	w := asyncOut
+	mu.Unlock()
+
+	if w == nil {
+		return nil
+	}
+	return w.flush(ctx)
+}
+
+type record struct {
+	level logrus.Level
+	data  []byte
+}
+
+// asyncWriter queues copies of the written entries, logrus reuses the
+// buffers it writes from, and writes them to out from its own goroutine.
+type asyncWriter struct {
+	out     io.Writer
+	size    int
+	level   logrus.Level
+	queue   chan record
+	done    chan struct{}
+	queued  atomic.Uint64
+	handled atomic.Uint64
+}
+
+func newAsyncWriter(out io.Writer, size int) *asyncWriter {
+	w := &asyncWriter{
+		out:   out,
+		size:  size,
+		queue: make(chan record, size),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *asyncWriter) setLevel(level logrus.Level) {
+	w.level = level
+}
+
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	r := record{level: w.level, data: bytes.Clone(p)}
+
+	policy := OverflowPolicy(overflowPolicy.Load())
+	if r.level <= logrus.FatalLevel {
+		// Never drop a fatal or panic entry, errorWriter then syncs to have
+		// it written before logrus exits or panics.
+		policy = OverflowBlock
+	}
+
+	switch policy {
+	case OverflowDropOldest:
+		for {
+			select {
+			case w.queue <- r:
+				w.queued.Add(1)
+				return len(p), nil
+			default:
+			}
+			select {
+			case <-w.queue:
+				counters.dropped.Add(1)
+				w.handled.Add(1)
+			default:
+			}
+		}
+	case OverflowDrop:
+		select {
+		case w.queue <- r:
+			w.queued.Add(1)
+		default:
+			counters.dropped.Add(1)
+		}
+	default:
+		w.queued.Add(1)
+		w.queue <- r
+	}
+	return len(p), nil
+}
+
+func (w *asyncWriter) run() {
+	defer close(w.done)
+
+	for r := range w.queue {
+		if lw, ok := w.out.(leveledWriter); ok {
+			lw.setLevel(r.level)
+		}
+		if _, err := w.out.Write(r.data); err != nil {
+			reportWriteError(err)
+		} else {
+			counters.written.Add(1)
+		}
+		w.handled.Add(1)
+	}
+}
+
+// Sync writes the buffered entries, waiting at most exitFlushTimeout, and
+// syncs the output.
+func (w *asyncWriter) Sync() error {
+	ctx, cancel := context.WithTimeout(context.Background(), exitFlushTimeout)
+	defer cancel()
+
+	if err := w.flush(ctx); err != nil {
+		return err
+	}
+	return syncWriter(w.out)
+}
+
+func (w *asyncWriter) flush(ctx context.Context) error {
+	target := w.queued.Load()
+
+	t := time.NewTicker(time.Millisecond)
+	defer t.Stop()
+
+	for w.handled.Load() < target {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	return nil
+}
+
+// close writes the remaining entries and stops the writer goroutine. The
+// writer must no longer be the output of a logger.
+func (w *asyncWriter) close() {
+	close(w.queue)
+	<-w.done
+}
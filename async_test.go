@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateWriter blocks every write until the gate is opened, signalling on
+// entered when a write starts.
+type gateWriter struct {
+	gate    chan struct{}
+	entered chan struct{}
+	mu      sync.Mutex
+	lines   []string
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{gate: make(chan struct{}), entered: make(chan struct{}, 16)}
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	w.entered <- struct{}{}
+	<-w.gate
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lines = append(w.lines, string(p))
+	return len(p), nil
+}
+
+func (w *gateWriter) written() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return strings.Join(w.lines, "")
+}
+
+func TestAsyncOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		policy OverflowPolicy
+		want   string
+	}{
+		{OverflowBlock, "123"},
+		{OverflowDropOldest, "13"},
+		{OverflowDrop, "12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			SetOverflowPolicy(tt.policy)
+			t.Cleanup(func() { SetOverflowPolicy(OverflowBlock) })
+
+			out := newGateWriter()
+			w := newAsyncWriter(out, 1)
+			w.setLevel(logrus.InfoLevel)
+
+			// 1 is taken by the writer goroutine, 2 fills the buffer and 3
+			// overflows it.
+			_, _ = w.Write([]byte("1"))
+			<-out.entered
+			_, _ = w.Write([]byte("2"))
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_, _ = w.Write([]byte("3"))
+			}()
+			if tt.policy != OverflowBlock {
+				<-done
+			}
+
+			close(out.gate)
+			<-done
+			w.close()
+
+			if got := out.written(); got != tt.want {
+				t.Fatalf("written %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAsyncFlush(t *testing.T) {
+	out := newGateWriter()
+	w := newAsyncWriter(out, 4)
+	w.setLevel(logrus.InfoLevel)
+	defer w.close()
+
+	_, _ = w.Write([]byte("1"))
+	_, _ = w.Write([]byte("2"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := w.flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("flush of a blocked writer = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(out.gate)
+	if err := w.flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.written(); got != "12" {
+		t.Fatalf("written %q after flush, want %q", got, "12")
+	}
+}
+
+func BenchmarkSync(b *testing.B) {
+	l, err := New(WithOutput(io.Discard))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.WithField("i", i).Info("benchmark")
+	}
+}
+
+func BenchmarkAsync(b *testing.B) {
+	l, err := New(WithOutput(io.Discard))
+	if err != nil {
+		b.Fatal(err)
+	}
+	w := newAsyncWriter(io.Discard, 1024)
+	l.Out = w
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.WithField("i", i).Info("benchmark")
+	}
+	b.StopTimer()
+	w.close()
+}
+
+func TestAsyncSetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	SetAsync(10)
+	t.Cleanup(func() {
+		SetAsync(0)
+		SetOutput(os.Stdout)
+	})
+
+	custom := logrus.New()
+	custom.Out = io.Discard
+	SetLogger(custom)
+	SetAsync(0)
+	SetLogger(nil)
+
+	GetLog().Info("still async")
+	if err := Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "still async") {
+		t.Fatalf("output %q, want the entry", buf.String())
+	}
+}
+
+func TestAsyncPanicWritten(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	SetAsync(10)
+	t.Cleanup(func() {
+		SetAsync(0)
+		SetOutput(os.Stdout)
+	})
+
+	func() {
+		defer func() { _ = recover() }()
+		GetLog().Panic("boom")
+	}()
+
+	if !strings.Contains(buf.String(), "boom") {
+		t.Fatalf("output %q, want the panic entry written before panicking", buf.String())
+	}
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+const EnvLogReportCaller = "LOG_REPORT_CALLER"
+
+const maxCallerDepth = 32
+
+var (
+	callerSkip atomic.Int32
+	// skipPackages are never reported as the caller: logrus itself, this
+	// package's helpers and the standard library front ends it adapts.
+	skipPackages = map[string]bool{
+		"github.com/sirupsen/logrus":         true,
+		reflect.TypeOf(entryKey{}).PkgPath(): true,
+		"log":                                true,
+		"log/slog":                           true,
+	}
+)
+
+// SetReportCaller enables or disables the "function" and "caller" (file:line)
+// fields on the package logger.
+func SetReportCaller(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	conf.ReportCaller = enabled
+	log.SetReportCaller(enabled)
+}
+
+// SetCallerSkip sets how many additional frames are skipped when reporting
+// the caller, for code that logs through its own wrapper functions. Frames of
+// logrus and of this package are always skipped.
+func SetCallerSkip(skip int) {
+	callerSkip.Store(int32(skip))
+}
+
+// callerHook replaces the caller found by logrus, which stops at the first
+// frame outside of logrus, with the first frame outside of skipPackages.
+type callerHook struct{}
+
+func (callerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (callerHook) Fire(e *logrus.Entry) error {
+	if e.Caller == nil {
+		return nil
+	}
+	if f, ok := caller(int(callerSkip.Load())); ok {
+		e.Caller = &f
+	}
+	return nil
+}
+
+func caller(skip int) (runtime.Frame, bool) {
+	pcs := make([]uintptr, maxCallerDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		f, more := frames.Next()
+		if !skipPackages[packageName(f.Function)] {
+			if skip == 0 {
+				return f, true
+			}
+			skip--
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// packageName returns the import path of a function name as reported by
+// runtime.Frame, e.g. "github.com/sirupsen/logrus" for
+// "github.com/sirupsen/logrus.(*Entry).Info".
+func packageName(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const EnvNoColor = "NO_COLOR"
+
+const consoleTimestampFormat = "15:04:05.000"
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiGray  = "\x1b[90m"
+)
+
+// ConsoleFormatter formats entries for reading in a terminal during
+// development: the time, the level, the message and the fields as key=value,
+// one entry per line.
+type ConsoleFormatter struct {
+	// TimestampFormat defaults to a compact time of day with milliseconds.
+	TimestampFormat string
+	// Colors prints the time in gray, the level in a level specific color
+	// and the message in bold.
+	Colors bool
+}
+
+// NewConsoleFormatter returns a ConsoleFormatter using colors when out is a
+// terminal and NO_COLOR is not set.
+func NewConsoleFormatter(out io.Writer) *ConsoleFormatter {
+	return &ConsoleFormatter{Colors: os.Getenv(EnvNoColor) == "" && isTerminal(out)}
+}
+
+func (f *ConsoleFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	b := e.Buffer
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+
+	layout := f.TimestampFormat
+	if layout == "" {
+		layout = consoleTimestampFormat
+	}
+
+	f.write(b, ansiGray, e.Time.Format(layout))
+	b.WriteByte(' ')
+	f.write(b, consoleLevelColor(e.Level), fmt.Sprintf("%-5s", consoleLevel(e.Level)))
+	b.WriteByte(' ')
+	f.write(b, ansiBold, strings.TrimSuffix(e.Message, "\n"))
+
+	keys := make([]string, 0, len(e.Data))
+	for k := range e.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b.WriteByte(' ')
+		f.write(b, ansiGray, k+"=")
+		b.WriteString(consoleValue(e.Data[k]))
+	}
+	if e.HasCaller() {
+		b.WriteByte(' ')
+		f.write(b, ansiGray, "caller=")
+		fmt.Fprintf(b, "%s:%d", e.Caller.File, e.Caller.Line)
+	}
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
+
+func (f *ConsoleFormatter) write(b *bytes.Buffer, color, s string) {
+	if !f.Colors {
+		b.WriteString(s)
+		return
+	}
+	b.WriteString(color)
+	b.WriteString(s)
+	b.WriteString(ansiReset)
+}
+
+func consoleLevel(level logrus.Level) string {
+	if level == logrus.WarnLevel {
+		return "WARN"
+	}
+	return strings.ToUpper(level.String())
+}
+
+func consoleLevelColor(level logrus.Level) string {
+	switch level {
+	case logrus.TraceLevel:
+		return ansiGray
+	case logrus.DebugLevel:
+		return "\x1b[35m"
+	case logrus.InfoLevel:
+		return "\x1b[32m"
+	case logrus.WarnLevel:
+		return "\x1b[33m"
+	default:
+		return "\x1b[31m"
+	}
+}
+
+// consoleValue formats v, quoting it when it would not read as one value.
+func consoleValue(v interface{}) string {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	default:
+		s = fmt.Sprint(v)
+	}
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// isTerminal reports whether w is a character device, e.g. a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+// ContextKey is the type of the context keys FromContext looks up. Values
+// stored under plain string keys are found as well.
+type ContextKey string
+
+type entryKey struct{}
+
+var (
+	contextKeysMu sync.RWMutex
+	contextKeys   = []string{"request_id", "trace_id", "span_id"}
+)
+
+// SetContextKeys replaces the keys FromContext extracts from a context.
+func SetContextKeys(keys ...string) {
+	contextKeysMu.Lock()
+	defer contextKeysMu.Unlock()
+
+	contextKeys = append([]string(nil), keys...)
+}
+
+// ContextWithLogger returns a copy of ctx carrying e, which FromContext uses
+// as its base entry.
+func ContextWithLogger(ctx context.Context, e *logrus.Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, e)
+}
+
+// FromContext returns the entry stored with ContextWithLogger, or an entry of
+// the package logger with the default fields, extended with the values of the
+// context keys found in ctx.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if ctx == nil {
+		return entry()
+	}
+
+	e, ok := ctx.Value(entryKey{}).(*logrus.Entry)
+	if !ok || e == nil {
+		e = entry()
+	}
+
+	contextKeysMu.RLock()
+	defer contextKeysMu.RUnlock()
+
+	data := make(logrus.Fields, len(contextKeys))
+	for _, k := range contextKeys {
+		v := ctx.Value(ContextKey(k))
+		if v == nil {
+			v = ctx.Value(k)
+		}
+		if v != nil {
+			data[k] = v
+		}
+	}
+
+	return e.WithContext(ctx).WithFields(data)
+}
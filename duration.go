@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// DurationEncoding selects how time.Duration field values are written.
+type DurationEncoding int
+
+const (
+	// DurationNanos writes an integer number of nanoseconds, as
+	// encoding/json does.
+	DurationNanos DurationEncoding = iota
+	// DurationMillis writes a number of milliseconds, with a fraction.
+	DurationMillis
+	// DurationString writes the duration as time.Duration.String, e.g. 1.5s.
+	DurationString
+)
+
+// SetDurationEncoding makes the package logger write every time.Duration
+// field value with enc. Unless enc is DurationNanos, time.Time field values
+// are normalized as well and written like the entry timestamp, following
+// the TimestampFormat of the logger.
+func SetDurationEncoding(enc DurationEncoding) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	setDurationHook(enc, conf.TimestampFormat)
+}
+
+// setDurationHook replaces the duration hook of the package logger. It must
+// be called with mu held.
+func setDurationHook(enc DurationEncoding, layout string) {
+	p := stdPipeline()
+	if h, ok := findHook[*durationHook](p); ok {
+		p.removeHook(h)
+	}
+	if enc != DurationNanos {
+		p.addHook(&durationHook{enc: enc, layout: layout})
+	}
+}
+
+type durationHook struct {
+	enc    DurationEncoding
+	layout string
+}
+
+func (h *durationHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *durationHook) Fire(e *logrus.Entry) error {
+	for k, v := range e.Data {
+		switch v := v.(type) {
+		case time.Duration:
+			e.Data[k] = h.duration(v)
+		case time.Time:
+			e.Data[k] = h.time(v)
+		}
+	}
+	return nil
+}
+
+func (h *durationHook) duration(d time.Duration) interface{} {
+	if h.enc == DurationString {
+		return d.String()
+	}
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (h *durationHook) time(t time.Time) interface{} {
+	if unit, ok := epochUnits[h.layout]; ok {
+		return t.UnixNano() / int64(unit)
+	}
+	return t.Format(h.layout)
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const ecsVersion = "1.6.0"
+
+// ecsFields maps the fields set by this package and its helpers onto their
+// Elastic Common Schema names.
+var ecsFields = map[string]string{
+	logrus.ErrorKey: "error.message",
+	"service":       "service.name",
+	"version":       "service.version",
+	"pid":           "process.pid",
+	"hostname":      "host.hostname",
+	"request_id":    "http.request.id",
+	"method":        "http.request.method",
+	"path":          "url.path",
+	"status":        "http.response.status_code",
+	"bytes":         "http.response.body.bytes",
+	"trace_id":      "trace.id",
+	"span_id":       "span.id",
+}
+
+// ECSFormatter formats entries as Elastic Common Schema JSON. Known fields
+// are renamed into their ECS namespace (see ecsFields), other fields are kept
+// as they are. Nested ECS fields are written with dotted keys, which
+// Elasticsearch treats like objects.
+type ECSFormatter struct {
+	PrettyPrint bool
+}
+
+func NewECSFormatter() *ECSFormatter {
+	return &ECSFormatter{}
+}
+
+func (f *ECSFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	data := fieldData(e, 7)
+	for k, v := range data {
+		if name, ok := ecsFields[k]; ok {
+			delete(data, k)
+			data[name] = v
+		}
+	}
+	prefixClashes(data, "@timestamp", "log.level", "message", "ecs.version",
+		"log.origin.file.name", "log.origin.file.line", "log.origin.function")
+
+	data["@timestamp"] = e.Time.Format(time.RFC3339Nano)
+	data["log.level"] = e.Level.String()
+	data["message"] = e.Message
+	data["ecs.version"] = ecsVersion
+	if e.HasCaller() {
+		data["log.origin.file.name"] = e.Caller.File
+		data["log.origin.file.line"] = e.Caller.Line
+		data["log.origin.function"] = e.Caller.Function
+	}
+
+	return encodeJSON(e, data, f.PrettyPrint)
+}
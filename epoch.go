@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	TimestampUnix      = "unix"
+	TimestampUnixMilli = "unixmilli"
+	TimestampUnixMicro = "unixmicro"
+	TimestampUnixNano  = "unixnano"
+)
+
+// epochTimeKey is the time key of formatters wrapped by epochFormatter,
+// which never appears in the output.
+const epochTimeKey = "\x00time"
+
+var epochUnits = map[string]time.Duration{
+	TimestampUnix:      time.Second,
+	TimestampUnixMilli: time.Millisecond,
+	TimestampUnixMicro: time.Microsecond,
+	TimestampUnixNano:  time.Nanosecond,
+}
+
+// epochFormatter adds the entry time as an integer number of units since
+// the Unix epoch, so JSON output carries a number rather than a string. The
+// wrapped formatter must have its timestamp disabled.
+type epochFormatter struct {
+	logrus.Formatter
+	key  string
+	unit time.Duration
+}
+
+func (f *epochFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(e.Data)+1)
+	for k, v := range e.Data {
+		data[k] = v
+	}
+	prefixClashes(data, f.key)
+	data[f.key] = e.Time.UnixNano() / int64(f.unit)
+
+	c := *e
+	c.Data = data
+	return f.Formatter.Format(&c)
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"errors"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const StackTraceKey = "stacktrace"
+
+type stackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// WithError returns an entry of the package logger with the default fields
+// and err in the "error" field. When err, or an error it wraps, carries a
+// github.com/pkg/errors stack trace, the frames of the innermost one are
+// added in the "stacktrace" field. A nil err adds nothing.
+func WithError(err error) *logrus.Entry {
+	e := entry()
+	if err == nil {
+		return e
+	}
+
+	e = e.WithError(err)
+	if frames := stackFrames(err); frames != nil {
+		e = e.WithField(StackTraceKey, frames)
+	}
+	return e
+}
+
+func stackFrames(err error) []string {
+	var st pkgerrors.StackTrace
+	for ; err != nil; err = errors.Unwrap(err) {
+		if t, ok := err.(stackTracer); ok {
+			st = t.StackTrace()
+		}
+	}
+	if st == nil {
+		return nil
+	}
+
+	frames := make([]string, len(st))
+	for i, f := range st {
+		text, _ := f.MarshalText()
+		frames[i] = string(text)
+	}
+	return frames
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+var (
+	fatalMu       sync.Mutex
+	fatalHandlers []func()
+)
+
+// RegisterOnFatal adds a function run when the package logger exits after a
+// Fatal entry, once the buffered entries are flushed and before the process
+// exits. Handlers run in registration order and may still log; a panicking
+// handler does not keep the others from running. They are not run on Panic,
+// which may be recovered.
+func RegisterOnFatal(fn func()) {
+	fatalMu.Lock()
+	defer fatalMu.Unlock()
+
+	fatalHandlers = append(fatalHandlers, fn)
+}
+
+// exit is the ExitFunc of the package logger. By the time it runs the fatal
+// entry itself is written, see errorWriter and asyncWriter.
+func exit(code int) {
+	ctx, cancel := context.WithTimeout(context.Background(), exitFlushTimeout)
+	_ = Flush(ctx)
+	cancel()
+
+	fatalMu.Lock()
+	handlers := append([]func(){}, fatalHandlers...)
+	fatalMu.Unlock()
+
+	for _, fn := range handlers {
+		runFatalHandler(fn)
+	}
+	_ = Flush(context.Background())
+
+	os.Exit(code)
+}
+
+func runFatalHandler(fn func()) {
+	defer func() {
+		_ = recover()
+	}()
+	fn()
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"os"
+	"sync"
+)
+
+const (
+	EnvServiceName    = "SERVICE_NAME"
+	EnvServiceVersion = "SERVICE_VERSION"
+
+	EnvLogRuntimeMeta = "LOG_RUNTIME_META"
+	EnvHostname       = "HOSTNAME"
+	EnvPodName        = "POD_NAME"
+)
+
+var (
+	fieldsMu sync.RWMutex
+	fields   logrus.Fields
+)
+
+// SetDefaultFields replaces the fields attached to every entry created by
+// WithField and WithFields, including the ones populated from the environment.
+func SetDefaultFields(f logrus.Fields) {
+	data := make(logrus.Fields, len(f))
+	for k, v := range f {
+		data[k] = v
+	}
+
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+
+	fields = data
+}
+
+// WithField returns an entry of the package logger with the default fields
+// and the given field.
+func WithField(key string, value interface{}) *logrus.Entry {
+	return entry().WithField(key, value)
+}
+
+// WithFields returns an entry of the package logger with the default fields
+// and the given fields.
+func WithFields(f logrus.Fields) *logrus.Entry {
+	return entry().WithFields(f)
+}
+
+// entry returns a fresh entry holding a copy of the default fields, so the
+// returned entry can be extended without synchronization.
+func entry() *logrus.Entry {
+	fieldsMu.RLock()
+	defer fieldsMu.RUnlock()
+
+	return GetLog().WithFields(fields)
+}
+
+func envFields() logrus.Fields {
+	f := logrus.Fields{"pid": os.Getpid()}
+	if v := os.Getenv(EnvServiceName); v != "" {
+		f["service"] = v
+	}
+	if v := os.Getenv(EnvServiceVersion); v != "" {
+		f["version"] = v
+	}
+	return f
+}
+
+// runtimeMetadata returns the hostname, pid and, when set, container_id and
+// k8s_pod fields. They are computed once, on first use.
+var runtimeMetadata = sync.OnceValue(func() logrus.Fields {
+	f := logrus.Fields{"pid": os.Getpid()}
+
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = os.Getenv(EnvHostname)
+	}
+	if host == "" {
+		host = "unknown"
+	}
+	f["hostname"] = host
+
+	// Docker and Kubernetes set HOSTNAME to the container ID and to the pod
+	// name respectively.
+	if v := os.Getenv(EnvHostname); v != "" {
+		f["container_id"] = v
+	}
+	if v := os.Getenv(EnvPodName); v != "" {
+		f["k8s_pod"] = v
+	}
+	return f
+})
+
+// fieldsHook adds the logger default fields to the entries missing them.
+type fieldsHook struct {
+	fields logrus.Fields
+}
+
+func newFieldsHook(fields logrus.Fields) *fieldsHook {
+	h := &fieldsHook{fields: make(logrus.Fields, len(fields))}
+	for k, v := range fields {
+		h.fields[k] = v
+	}
+	return h
+}
+
+func (h *fieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *fieldsHook) Fire(e *logrus.Entry) error {
+	for k, v := range h.fields {
+		if _, ok := e.Data[k]; !ok {
+			e.Data[k] = v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// AddFilter adds a predicate deciding whether an entry of the package logger
+// is written: returning false discards it. An entry is written only when all
+// filters keep it. Filters run in the order they were added, after the hooks
+// and before formatting and sampling, e.g.
+//
+//	logger.AddFilter(func(e *logrus.Entry) bool {
+//		return e.Data["path"] != "/healthz"
+//	})
+//
+// Filtered entries still fire every hook, those added with AddHook, OnLevel,
+// the Recorder of Test and the logprom hooks included. Filters are called
+// with the logger locked and must not log.
+func AddFilter(keep func(e *logrus.Entry) bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	stdPipeline().addFilter(keep)
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"flag"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+var (
+	flagMu     sync.Mutex
+	flagLevel  levelFlag
+	flagFormat formatFlag
+	// applied holds the flag values pushed by ApplyFlags, kept over reloads
+	// of the environment configuration.
+	applied struct{ level, format string }
+)
+
+// RegisterFlags registers the -log-level and -log-format flags on fs, or on
+// flag.CommandLine when fs is nil. Call ApplyFlags once the flags are parsed.
+//
+// The configuration follows the precedence flag > environment > default: a
+// flag given on the command line overrides LOG_LEVEL or LOG_FORMAT, which
+// override the package defaults.
+func RegisterFlags(fs *flag.FlagSet) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	fs.Var(&flagLevel, "log-level", "log level (trace, debug, info, warn, error, fatal, panic), overrides "+EnvLogLevel)
+	fs.Var(&flagFormat, "log-format", "log format (json, text, logfmt, console), overrides "+EnvLogFormat)
+}
+
+// ApplyFlags configures the package logger with the flags registered by
+// RegisterFlags that were set on the command line. Flags left unset keep the
+// environment or default configuration.
+func ApplyFlags() error {
+	flagMu.Lock()
+	level, format := flagLevel.value, flagFormat.value
+	flagMu.Unlock()
+
+	if level != "" {
+		if err := SetLevel(level); err != nil {
+			return err
+		}
+	}
+	if format != "" {
+		if err := setFormat(format); err != nil {
+			return err
+		}
+	}
+
+	flagMu.Lock()
+	defer flagMu.Unlock()
+
+	applied.level, applied.format = level, format
+	return nil
+}
+
+// withFlags overrides cfg with the flag values applied by ApplyFlags.
+func withFlags(cfg Config) Config {
+	flagMu.Lock()
+	defer flagMu.Unlock()
+
+	if applied.level != "" {
+		cfg.Level = applied.level
+	}
+	if applied.format != "" {
+		cfg.Format = applied.format
+	}
+	return cfg
+}
+
+func setFormat(format string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	p := stdPipeline()
+	if p.custom {
+		return errCustomFormatter
+	}
+	cfg := conf
+	cfg.Format = format
+
+	p.mu.RLock()
+	lvl := p.level
+	p.mu.RUnlock()
+
+	f, err := newFormatter(cfg, lvl)
+	if err != nil {
+		return err
+	}
+	conf = cfg
+	p.setFormatter(f)
+
+	return nil
+}
+
+type levelFlag struct {
+	value string
+}
+
+func (f *levelFlag) String() string {
+	return f.value
+}
+
+func (f *levelFlag) Set(s string) error {
+	if _, err := logrus.ParseLevel(s); err != nil {
+		return err
+	}
+
+	flagMu.Lock()
+	defer flagMu.Unlock()
+
+	f.value = s
+	return nil
+}
+
+type formatFlag struct {
+	value string
+}
+
+func (f *formatFlag) String() string {
+	return f.value
+}
+
+func (f *formatFlag) Set(s string) error {
+	if !validFormat(s) {
+		return fmt.Errorf("not a valid log format: %q", s)
+	}
+
+	flagMu.Lock()
+	defer flagMu.Unlock()
+
+	f.value = s
+	return nil
+}
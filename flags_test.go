@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+)
+
+func TestFlagPrecedence(t *testing.T) {
+	SetOutput(&bytes.Buffer{})
+	t.Cleanup(func() {
+		resetFlags()
+		_ = SetLevel("info")
+		SetOutput(os.Stdout)
+	})
+
+	tests := []struct {
+		name string
+		env  string
+		args []string
+		want string
+	}{
+		{"default", "", nil, "info"},
+		{"env", "debug", nil, "debug"},
+		{"flag", "", []string{"-log-level", "error"}, "error"},
+		{"flag over env", "debug", []string{"-log-level", "warn"}, "warning"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			t.Setenv(EnvLogLevel, tt.env)
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			RegisterFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			reload()
+			if err := ApplyFlags(); err != nil {
+				t.Fatal(err)
+			}
+			if got := GetLevel(); got != tt.want {
+				t.Fatalf("level after ApplyFlags = %q, want %q", got, tt.want)
+			}
+
+			// A reload of the environment keeps the flags on top.
+			reload()
+			if got := GetLevel(); got != tt.want {
+				t.Fatalf("level after reload = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func resetFlags() {
+	flagMu.Lock()
+	defer flagMu.Unlock()
+
+	flagLevel.value, flagFormat.value = "", ""
+	applied.level, applied.format = "", ""
+}
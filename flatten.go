@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"sort"
+	"sync/atomic"
+)
+
+const defaultFlattenDepth = 3
+
+const flattenCycle = "[cycle]"
+
+// SetFlattenFields enables or disables flattening of nested field maps on
+// the package logger: {"ctx": {"user_id": 1}} is logged as {"ctx.user_id": 1}.
+// Only maps with string keys are flattened, up to the depth set with
+// SetFlattenDepth; deeper maps are kept as values. A flattened key never
+// replaces a field set explicitly, it is logged as "fields.<key>" instead,
+// and a map containing itself is logged as "[cycle]" where it repeats.
+func SetFlattenFields(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	p := stdPipeline()
+	h, ok := findHook[*flattenHook](p)
+	switch {
+	case enabled && !ok:
+		h = &flattenHook{}
+		h.depth.Store(defaultFlattenDepth)
+		p.addHook(h)
+	case !enabled && ok:
+		p.removeHook(h)
+	}
+}
+
+// SetFlattenDepth sets how many levels of nested maps SetFlattenFields
+// flattens. The default is 3.
+func SetFlattenDepth(depth int) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if h, ok := findHook[*flattenHook](stdPipeline()); ok {
+		h.depth.Store(int32(depth))
+	}
+}
+
+type flattenHook struct {
+	depth atomic.Int32
+}
+
+func (h *flattenHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *flattenHook) Fire(e *logrus.Entry) error {
+	depth := int(h.depth.Load())
+
+	var nested []string
+	for k, v := range e.Data {
+		if isStringMap(v) {
+			nested = append(nested, k)
+		}
+	}
+	if len(nested) == 0 || depth < 1 {
+		return nil
+	}
+	sort.Strings(nested)
+
+	for _, k := range nested {
+		v := e.Data[k]
+		delete(e.Data, k)
+		m := reflect.ValueOf(v)
+		flatten(e.Data, k, m, depth, map[uintptr]bool{m.Pointer(): true})
+	}
+	return nil
+}
+
+// flatten adds the entries of the map m under prefix, recursing into nested
+// maps while depth allows. Keys are visited in sorted order so collisions
+// resolve the same way every time, seen holds the maps on the current path.
+func flatten(data logrus.Fields, prefix string, m reflect.Value, depth int, seen map[uintptr]bool) {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	for _, k := range keys {
+		key := prefix + "." + k.String()
+		v := m.MapIndex(k)
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			v = v.Elem()
+		}
+		value := v.Interface()
+		if isStringMap(value) {
+			switch ptr := v.Pointer(); {
+			case seen[ptr]:
+				value = flattenCycle
+			case depth > 1:
+				seen[ptr] = true
+				flatten(data, key, v, depth-1, seen)
+				delete(seen, ptr)
+				continue
+			}
+		}
+		if _, ok := data[key]; ok {
+			key = "fields." + key
+		}
+		data[key] = value
+	}
+}
+
+func isStringMap(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && !reflect.ValueOf(v).IsNil()
+}
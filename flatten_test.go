@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlattenRedactsNestedFields(t *testing.T) {
+	_, r := Test(t)
+
+	SetFlattenFields(true)
+	t.Cleanup(func() { SetFlattenFields(false) })
+	AddRedactedFields("password")
+
+	WithField("req", map[string]interface{}{"password": "hunter2", "user": "bob"}).Info("login")
+
+	out := r.Output()
+	if strings.Contains(out, "hunter2") {
+		t.Fatalf("output %q leaks the password", out)
+	}
+	if !strings.Contains(out, `"req.password":"`+Redacted+`"`) || !strings.Contains(out, `"req.user":"bob"`) {
+		t.Fatalf("output %q, want flattened req fields", out)
+	}
+}
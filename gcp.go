@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"os"
+	"time"
+)
+
+const EnvGoogleCloudProject = "GOOGLE_CLOUD_PROJECT"
+
+const (
+	gcpTrace          = "logging.googleapis.com/trace"
+	gcpSpanID         = "logging.googleapis.com/spanId"
+	gcpSourceLocation = "logging.googleapis.com/sourceLocation"
+)
+
+// GCPFormatter formats entries as Cloud Logging structured JSON: uppercase
+// severities, the message in "message", and the trace_id and span_id fields
+// mapped onto the special trace fields so entries are correlated with traces.
+type GCPFormatter struct {
+	// ProjectID qualifies trace IDs as "projects/<id>/traces/<trace_id>".
+	ProjectID   string
+	PrettyPrint bool
+}
+
+// NewGCPFormatter returns a GCPFormatter for the project set in
+// GOOGLE_CLOUD_PROJECT.
+func NewGCPFormatter() *GCPFormatter {
+	return &GCPFormatter{ProjectID: os.Getenv(EnvGoogleCloudProject)}
+}
+
+func (f *GCPFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	data := fieldData(e, 6)
+	prefixClashes(data, "timestamp", "severity", "message", gcpTrace, gcpSpanID, gcpSourceLocation)
+
+	data["timestamp"] = e.Time.Format(time.RFC3339Nano)
+	data["severity"] = gcpSeverity(e.Level)
+	data["message"] = e.Message
+
+	if id, ok := e.Data["trace_id"]; ok {
+		if f.ProjectID != "" {
+			data[gcpTrace] = fmt.Sprintf("projects/%s/traces/%v", f.ProjectID, id)
+		} else {
+			data[gcpTrace] = fmt.Sprint(id)
+		}
+	}
+	if id, ok := e.Data["span_id"]; ok {
+		data[gcpSpanID] = fmt.Sprint(id)
+	}
+	if e.HasCaller() {
+		data[gcpSourceLocation] = map[string]interface{}{
+			"file":     e.Caller.File,
+			"line":     e.Caller.Line,
+			"function": e.Caller.Function,
+		}
+	}
+
+	return encodeJSON(e, data, f.PrettyPrint)
+}
+
+func gcpSeverity(level logrus.Level) string {
+	switch level {
+	case logrus.TraceLevel, logrus.DebugLevel:
+		return "DEBUG"
+	case logrus.InfoLevel:
+		return "INFO"
+	case logrus.WarnLevel:
+		return "WARNING"
+	case logrus.ErrorLevel:
+		return "ERROR"
+	case logrus.FatalLevel:
+		return "CRITICAL"
+	case logrus.PanicLevel:
+		return "ALERT"
+	default:
+		return "DEFAULT"
+	}
+}
@@ -1,10 +1,14 @@
 module github.com/go-funcards/logger
 
-go 1.18
+go 1.21
 
-require github.com/sirupsen/logrus v1.9.0
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/sirupsen/logrus v1.9.0
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1
+)
 
 require (
-	golang.org/x/sys v0.0.0-20220715151400-c0bba94af5f8 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
+	github.com/stretchr/testify v1.9.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
 )
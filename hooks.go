@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// AddHook adds hook to the package logger, the same logger GetLog returns.
+// Hooks fire in the order they were added, after the internal hooks of this
+// package (caller reporting, redaction, ...), so they observe the entry as it
+// will be formatted, and only for the entries passing the level of their
+// module. A hook added with GetLog().AddHook instead is fired by logrus for
+// every entry its level lets through.
+//
+// Hooks may be added while other goroutines are logging; an entry being
+// logged concurrently may or may not see the new hook.
+func AddHook(hook logrus.Hook) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	stdPipeline().addHook(userHook{hook})
+}
+
+// ClearHooks removes every hook added with AddHook or GetLog().AddHook. The
+// internal hooks of this package are kept.
+func ClearHooks() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	p := stdPipeline()
+	p.removeUserHooks()
+
+	hooks := make(logrus.LevelHooks)
+	hooks.Add(p)
+	log.ReplaceHooks(hooks)
+}
+
+// userHook marks the hooks added with AddHook among those of the pipeline.
+type userHook struct {
+	logrus.Hook
+}
+
+// OnLevel calls fn with the entries of the package logger at level or more
+// severe, e.g. OnLevel(logrus.ErrorLevel, capture) for every error, fatal
+// and panic. fn runs synchronously, before the entry is written, and gets a
+// copy of the entry: changing its fields does not change what is logged. fn
+// may log, no lock is held while it runs; the entries it logs are written
+// but not passed to fn, nor to the other OnLevel callbacks, so it never
+// calls itself again. Telling those entries apart walks the stack of every
+// entry logged at a watched level while a callback is running. The returned
+// function removes the callback.
+func OnLevel(level logrus.Level, fn func(e *logrus.Entry)) (remove func()) {
+	return addLevelHook(&levelHook{level: level, fn: fn})
+}
+
+// OnLevelAsync is like OnLevel, but fn runs on a worker goroutine fed
+// through a buffer of bufSize entries, at least one, so a slow fn does not
+// delay logging. Entries arriving while the buffer is full are not passed to
+// fn, and neither are the entries fn logs, so fn logging never blocks the
+// worker on its own buffer nor feeds it. The returned function removes the
+// callback and stops the worker once the buffered entries are handled.
+func OnLevelAsync(level logrus.Level, fn func(e *logrus.Entry), bufSize int) (remove func()) {
+	if bufSize < 1 {
+		bufSize = 1
+	}
+
+	h := &levelHook{level: level, fn: fn, queue: make(chan *logrus.Entry, bufSize)}
+	go func() {
+		for e := range h.queue {
+			callLevelFn(fn, e)
+		}
+	}()
+
+	unregister := addLevelHook(h)
+	return func() {
+		unregister()
+		h.stop()
+	}
+}
+
+func addLevelHook(h *levelHook) (remove func()) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	p := stdPipeline()
+	p.addHook(h)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { p.removeHook(h) })
+	}
+}
+
+type levelHook struct {
+	level logrus.Level
+	fn    func(e *logrus.Entry)
+	// queue feeds the worker of OnLevelAsync, closed by stop.
+	queue   chan *logrus.Entry
+	mu      sync.RWMutex
+	stopped bool
+}
+
+func (h *levelHook) Levels() []logrus.Level {
+	return logrus.AllLevels[:h.level+1]
+}
+
+func (h *levelHook) Fire(e *logrus.Entry) error {
+	if inCallback() {
+		return nil
+	}
+
+	c := *e
+	c.Buffer = nil
+	c.Data = make(logrus.Fields, len(e.Data))
+	for k, v := range e.Data {
+		c.Data[k] = v
+	}
+
+	if h.queue == nil {
+		callLevelFn(h.fn, &c)
+		return nil
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.stopped {
+		return nil
+	}
+	// Never block logging on a slow fn.
+	select {
+	case h.queue <- &c:
+	default:
+	}
+	return nil
+}
+
+func (h *levelHook) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.stopped {
+		h.stopped = true
+		close(h.queue)
+	}
+}
+
+var (
+	// levelFnName is the name of callLevelFn as found on the stack.
+	levelFnName = runtime.FuncForPC(reflect.ValueOf(callLevelFn).Pointer()).Name()
+	// levelFns counts the running callbacks, so that the stack is only
+	// walked while there is one.
+	levelFns atomic.Int32
+)
+
+// callLevelFn runs an OnLevel or OnLevelAsync callback, marking the stack of
+// the calling goroutine for inCallback.
+func callLevelFn(fn func(e *logrus.Entry), e *logrus.Entry) {
+	levelFns.Add(1)
+	defer levelFns.Add(-1)
+
+	fn(e)
+}
+
+// inCallback reports whether the calling goroutine is logging from within an
+// OnLevel or OnLevelAsync callback.
+func inCallback() bool {
+	if levelFns.Load() == 0 {
+		return false
+	}
+
+	pcs := make([]uintptr, maxCallerDepth)
+	for {
+		n := runtime.Callers(2, pcs)
+		frames := runtime.CallersFrames(pcs[:n])
+		for {
+			f, more := frames.Next()
+			if f.Function == levelFnName {
+				return true
+			}
+			if !more {
+				break
+			}
+		}
+		if n < len(pcs) {
+			return false
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOnLevelLogs(t *testing.T) {
+	_, r := Test(t)
+
+	var calls int
+	remove := OnLevel(logrus.ErrorLevel, func(e *logrus.Entry) {
+		calls++
+		GetLog().WithField("cause", e.Message).Error("error reported")
+	})
+	t.Cleanup(remove)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		GetLog().Error("failed")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("logging from an OnLevel callback did not return")
+	}
+	if calls != 1 {
+		t.Fatalf("fn called %d times, want 1", calls)
+	}
+	if out := r.Output(); !strings.Contains(out, "error reported") {
+		t.Fatalf("output %q, want the entry logged by fn", out)
+	}
+}
+
+func TestOnLevelAsyncLogs(t *testing.T) {
+	_, r := Test(t)
+
+	var calls atomic.Int32
+	called := make(chan struct{}, 1)
+	remove := OnLevelAsync(logrus.ErrorLevel, func(e *logrus.Entry) {
+		calls.Add(1)
+		GetLog().WithField("cause", e.Message).Error("error reported")
+		called <- struct{}{}
+	}, 0)
+	t.Cleanup(remove)
+
+	GetLog().Error("failed")
+	select {
+	case <-called:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fn was not called")
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("fn called %d times, want 1", n)
+	}
+	if out := r.Output(); !strings.Contains(out, "error reported") {
+		t.Fatalf("output %q, want the entry logged by fn", out)
+	}
+}
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"net"
+	"net/http"
+	"time"
+)
+
+const DefaultRequestIDHeader = "X-Request-ID"
+
+type HTTPOption func(*httpOptions)
+
+type httpOptions struct {
+	skipPaths       map[string]bool
+	requestIDHeader string
+}
+
+// WithSkipPaths disables the access log for the given request paths, e.g.
+// health checks.
+func WithSkipPaths(paths ...string) HTTPOption {
+	return func(o *httpOptions) {
+		for _, p := range paths {
+			o.skipPaths[p] = true
+		}
+	}
+}
+
+// WithRequestIDHeader sets the header the request ID is read from and echoed
+// in. It defaults to DefaultRequestIDHeader.
+func WithRequestIDHeader(name string) HTTPOption {
+	return func(o *httpOptions) {
+		o.requestIDHeader = name
+	}
+}
+
+// HTTPMiddleware is NewHTTPMiddleware with the default options.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return NewHTTPMiddleware()(next)
+}
+
+// NewHTTPMiddleware returns a middleware logging one entry per request with
+// its method, path, status, size and latency. Server errors are logged at
+// error level, client errors at warn and everything else at info. When the
+// request context is done by the time the handler returns, e.g. because the
+// client went away, the entry is logged at least at warn with
+// client_disconnected=true and the context error.
+//
+// The request ID is taken from the request header or generated, echoed in
+// the response and attached to the entry stored in the request context, so
+// handlers logging through FromContext carry it as well.
+func NewHTTPMiddleware(opts ...HTTPOption) func(http.Handler) http.Handler {
+	o := httpOptions{
+		skipPaths:       make(map[string]bool),
+		requestIDHeader: DefaultRequestIDHeader,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if o.skipPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+
+			id := r.Header.Get(o.requestIDHeader)
+			if id == "" {
+				id = NewRequestID()
+			}
+			w.Header().Set(o.requestIDHeader, id)
+
+			ctx := context.WithValue(r.Context(), ContextKey("request_id"), id)
+			e := FromContext(ctx)
+			ctx = ContextWithLogger(ctx, e)
+
+			rw := &responseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			fields := logrus.Fields{
+				"method":  r.Method,
+				"path":    r.URL.Path,
+				"status":  status,
+				"bytes":   rw.bytes,
+				"latency": time.Since(start),
+			}
+			level := statusLevel(status)
+			if err := ctx.Err(); err != nil {
+				fields["client_disconnected"] = true
+				fields[logrus.ErrorKey] = err.Error()
+				if level > logrus.WarnLevel {
+					level = logrus.WarnLevel
+				}
+			}
+
+			e.WithFields(fields).Log(level, "http request")
+		})
+	}
+}
+
+func statusLevel(status int) logrus.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return logrus.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return logrus.WarnLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
+
+// NewRequestID returns a random 128-bit hex encoded request ID.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// responseWriter records the status and size of a response. It forwards
+// Flush, Hijack and Push to the underlying writer when supported and exposes
+// it through Unwrap for http.ResponseController.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 && code >= http.StatusOK {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("logger: response writer does not implement http.Hijacker")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+func (w *responseWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,313 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultSinkBatchSize     = 100
+	defaultSinkFlushInterval = time.Second
+	defaultSinkMaxBacklog    = 10000
+	defaultSinkMaxRetries    = 3
+	defaultSinkRetryBackoff  = 500 * time.Millisecond
+	defaultSinkTimeout       = 10 * time.Second
+	defaultSinkCloseTimeout  = 5 * time.Second
+)
+
+var errSinkClosed = errors.New("http log sink closed")
+
+// HTTPSinkOptions configures NewHTTPSink. Zero values use the defaults.
+type HTTPSinkOptions struct {
+	// BatchSize is the number of lines sent per request, 100 by default.
+	BatchSize int
+	// FlushInterval is the longest a line waits before being sent, 1s by
+	// default.
+	FlushInterval time.Duration
+	// MaxBacklog is the number of lines kept while the endpoint is slow or
+	// down, 10000 by default. Lines written beyond it are dropped.
+	MaxBacklog int
+	// MaxRetries is the number of retries of a batch failing with a network
+	// error, a 429 or a 5xx, 3 by default. Negative disables retries.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled for every
+	// further one, 500ms by default.
+	RetryBackoff time.Duration
+	// Headers are added to every request, e.g. Authorization.
+	Headers http.Header
+	// Gzip compresses the request bodies.
+	Gzip bool
+	// Client sends the requests, a client with a 10s timeout by default.
+	Client *http.Client
+	// CloseTimeout bounds how long Close keeps sending the remaining lines,
+	// retries included, 5s by default. Lines still unsent are dropped.
+	CloseTimeout time.Duration
+}
+
+// NewHTTPSink returns an output POSTing the written lines in batches of
+// newline delimited JSON to url, for use with SetOutput or SetOutputs:
+//
+//	sink, err := logger.NewHTTPSink("https://logs.example.com/ingest", logger.HTTPSinkOptions{
+//		Headers: http.Header{"Authorization": {"Bearer " + token}},
+//		Gzip:    true,
+//	})
+//
+// Writes never block on the endpoint. Batches that still fail after the
+// retries, and lines dropped because the backlog is full, are counted and
+// reported in a warning logged through the package logger. Errors sending a
+// batch are passed to the handler set with SetErrorHandler. Close sends the
+// remaining lines, giving up after CloseTimeout.
+func NewHTTPSink(rawURL string, opts HTTPSinkOptions) (io.WriteCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("not a valid log sink URL: %q", rawURL)
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultSinkBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultSinkFlushInterval
+	}
+	if opts.MaxBacklog <= 0 {
+		opts.MaxBacklog = defaultSinkMaxBacklog
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultSinkMaxRetries
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = defaultSinkRetryBackoff
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: defaultSinkTimeout}
+	}
+	if opts.CloseTimeout <= 0 {
+		opts.CloseTimeout = defaultSinkCloseTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &httpSink{
+		url:    u.String(),
+		opts:   opts,
+		ready:  make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		exit:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	go s.run()
+	return s, nil
+}
+
+type httpSink struct {
+	url  string
+	opts HTTPSinkOptions
+
+	mu      sync.Mutex
+	lines   [][]byte
+	closed  bool
+	dropped atomic.Uint64
+
+	// ready signals a full batch, done asks run to send what is left and
+	// return, which it signals by closing exit.
+	ready chan struct{}
+	done  chan struct{}
+	exit  chan struct{}
+	// ctx is cancelled once the CloseTimeout of Close is over, aborting the
+	// request and the backoff in progress.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *httpSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, errSinkClosed
+	}
+	if len(s.lines) >= s.opts.MaxBacklog {
+		s.dropped.Add(1)
+		counters.dropped.Add(1)
+		return len(p), nil
+	}
+
+	s.lines = append(s.lines, bytes.Clone(p))
+	if len(s.lines) >= s.opts.BatchSize {
+		select {
+		case s.ready <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Close sends the buffered lines, for at most CloseTimeout, and stops the
+// sink. Lines written after Close are rejected.
+func (s *httpSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.done)
+
+	t := time.NewTimer(s.opts.CloseTimeout)
+	defer t.Stop()
+
+	select {
+	case <-s.exit:
+	case <-t.C:
+	}
+	s.cancel()
+	<-s.exit
+	return nil
+}
+
+func (s *httpSink) run() {
+	defer close(s.exit)
+
+	t := time.NewTicker(s.opts.FlushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			s.flush(true)
+			return
+		case <-s.ready:
+			s.flush(false)
+		case <-t.C:
+			s.flush(true)
+		}
+	}
+}
+
+// flush sends the buffered lines in batches, all of them when partial is
+// set, only the full batches otherwise.
+func (s *httpSink) flush(partial bool) {
+	for {
+		s.mu.Lock()
+		if s.ctx.Err() != nil {
+			// Close gave up, drop what is left.
+			s.dropped.Add(uint64(len(s.lines)))
+			counters.dropped.Add(uint64(len(s.lines)))
+			s.lines = nil
+		}
+		n := len(s.lines)
+		if n > s.opts.BatchSize {
+			n = s.opts.BatchSize
+		}
+		if n == 0 || (n < s.opts.BatchSize && !partial) {
+			s.mu.Unlock()
+			break
+		}
+		batch := s.lines[:n:n]
+		s.lines = s.lines[n:]
+		s.mu.Unlock()
+
+		if err := s.send(batch); err != nil {
+			s.dropped.Add(uint64(len(batch)))
+			counters.dropped.Add(uint64(len(batch)))
+			reportWriteError(err)
+		}
+	}
+
+	if n := s.dropped.Swap(0); n > 0 {
+		GetLog().WithField("dropped", n).Warn("log lines dropped by the HTTP sink")
+	}
+}
+
+// send posts batch, retrying transient failures with an exponential
+// backoff.
+func (s *httpSink) send(batch [][]byte) error {
+	body, err := s.body(batch)
+	if err != nil {
+		return err
+	}
+
+	backoff := s.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body)
+		if err == nil || !retry || attempt >= s.opts.MaxRetries {
+			return err
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-s.ctx.Done():
+			t.Stop()
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+func (s *httpSink) body(batch [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+
+	var zw *gzip.Writer
+	if s.opts.Gzip {
+		zw = gzip.NewWriter(&buf)
+		w = zw
+	}
+	for _, line := range batch {
+		if _, err := w.Write(line); err != nil {
+			return nil, err
+		}
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// post sends one request and reports whether a failure is worth a retry.
+func (s *httpSink) post(body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for k, v := range s.opts.Headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if s.opts.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send logs, %w", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("failed to send logs, %s", resp.Status)
+	default:
+		return false, fmt.Errorf("failed to send logs, %s", resp.Status)
+	}
+}
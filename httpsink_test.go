@@ -0,0 +1,191 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sinkServer records the body of every request, failing the first fail
+// ones with status.
+type sinkServer struct {
+	*httptest.Server
+	requests chan string
+	calls    atomic.Int32
+}
+
+func newSinkServer(t *testing.T, fail int32, status int) *sinkServer {
+	s := &sinkServer{requests: make(chan string, 100)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.calls.Add(1) <= fail {
+			w.WriteHeader(status)
+			return
+		}
+
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			body = zr
+		}
+		b, err := io.ReadAll(body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		s.requests <- string(b)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *sinkServer) next(t *testing.T) string {
+	t.Helper()
+
+	select {
+	case body := <-s.requests:
+		return body
+	case <-time.After(5 * time.Second):
+		t.Fatal("no request received")
+		return ""
+	}
+}
+
+func newTestSink(t *testing.T, url string, opts HTTPSinkOptions) io.WriteCloser {
+	t.Helper()
+
+	sink, err := NewHTTPSink(url, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sink.Close() })
+	return sink
+}
+
+func writeLines(t *testing.T, w io.Writer, n int) {
+	t.Helper()
+
+	for i := 1; i <= n; i++ {
+		if _, err := fmt.Fprintf(w, "{\"n\":%d}\n", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHTTPSinkBatchSize(t *testing.T) {
+	srv := newSinkServer(t, 0, 0)
+	sink := newTestSink(t, srv.URL, HTTPSinkOptions{BatchSize: 2, FlushInterval: time.Hour, Gzip: true})
+
+	writeLines(t, sink, 4)
+
+	if got := srv.next(t); got != "{\"n\":1}\n{\"n\":2}\n" {
+		t.Fatalf("first batch %q", got)
+	}
+	if got := srv.next(t); got != "{\"n\":3}\n{\"n\":4}\n" {
+		t.Fatalf("second batch %q", got)
+	}
+}
+
+func TestHTTPSinkFlushInterval(t *testing.T) {
+	srv := newSinkServer(t, 0, 0)
+	sink := newTestSink(t, srv.URL, HTTPSinkOptions{FlushInterval: 10 * time.Millisecond})
+
+	writeLines(t, sink, 1)
+
+	if got := srv.next(t); strings.Count(got, "\n") != 1 {
+		t.Fatalf("batch %q, want the line", got)
+	}
+}
+
+func TestHTTPSinkRetry(t *testing.T) {
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			srv := newSinkServer(t, 2, status)
+			sink := newTestSink(t, srv.URL, HTTPSinkOptions{BatchSize: 1, RetryBackoff: time.Millisecond})
+
+			writeLines(t, sink, 1)
+
+			if got := srv.next(t); strings.Count(got, "\n") != 1 {
+				t.Fatalf("batch %q, want the line", got)
+			}
+			if n := srv.calls.Load(); n != 3 {
+				t.Fatalf("%d requests, want 3", n)
+			}
+		})
+	}
+}
+
+func TestHTTPSinkBacklog(t *testing.T) {
+	_, r := Test(t)
+	srv := newSinkServer(t, 0, 0)
+	sink := newTestSink(t, srv.URL, HTTPSinkOptions{MaxBacklog: 2, FlushInterval: time.Hour})
+
+	before := Stats().Dropped
+	writeLines(t, sink, 5)
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := srv.next(t); strings.Count(got, "\n") != 2 {
+		t.Fatalf("batch %q, want the 2 lines of the backlog", got)
+	}
+	if n := Stats().Dropped - before; n != 3 {
+		t.Fatalf("%d lines dropped, want 3", n)
+	}
+	if out := r.Output(); !strings.Contains(out, `"dropped":3`) {
+		t.Fatalf("output %q, want a warning with the dropped lines", out)
+	}
+}
+
+func TestHTTPSinkClose(t *testing.T) {
+	srv := newSinkServer(t, 0, 0)
+	sink := newTestSink(t, srv.URL, HTTPSinkOptions{FlushInterval: time.Hour})
+
+	writeLines(t, sink, 3)
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := srv.next(t); strings.Count(got, "\n") != 3 {
+		t.Fatalf("batch %q, want the 3 lines", got)
+	}
+	if _, err := sink.Write([]byte("late\n")); err == nil {
+		t.Fatal("write after Close succeeded")
+	}
+}
+
+func TestHTTPSinkCloseTimeout(t *testing.T) {
+	_, _ = Test(t)
+	SetErrorHandler(func(err error) {})
+	t.Cleanup(func() { SetErrorHandler(nil) })
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	sink := newTestSink(t, srv.URL, HTTPSinkOptions{BatchSize: 1, CloseTimeout: 50 * time.Millisecond})
+	writeLines(t, sink, 10)
+
+	start := time.Now()
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Fatalf("Close took %s", d)
+	}
+}
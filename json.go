@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+)
+
+// fieldData copies the entry fields for encoding. Errors are replaced with
+// their message, encoding/json would render them as {}.
+func fieldData(e *logrus.Entry, extra int) logrus.Fields {
+	data := make(logrus.Fields, len(e.Data)+extra)
+	for k, v := range e.Data {
+		switch v := v.(type) {
+		case error:
+			data[k] = v.Error()
+		default:
+			data[k] = v
+		}
+	}
+	return data
+}
+
+// prefixClashes moves fields colliding with the given keys to "fields.<key>",
+// as logrus does for its built-in keys.
+func prefixClashes(data logrus.Fields, keys ...string) {
+	for _, k := range keys {
+		if v, ok := data[k]; ok {
+			data["fields."+k] = v
+			delete(data, k)
+		}
+	}
+}
+
+// encodeJSON encodes data as one JSON line, reusing the entry buffer when
+// logrus provides one.
+func encodeJSON(e *logrus.Entry, data interface{}, prettyPrint bool) ([]byte, error) {
+	b := e.Buffer
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+
+	enc := json.NewEncoder(b)
+	if prettyPrint {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal fields to JSON, %w", err)
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// LazyValue is a field value computed only when its entry is emitted.
+type LazyValue struct {
+	fn func() interface{}
+}
+
+// Lazy returns a field value replaced by the result of fn when the entry is
+// emitted, e.g. WithField("state", Lazy(dump)).Debug("..."). fn is never
+// called for entries suppressed by the logger or module level, but it may be
+// for entries dropped later by sampling or a filter. The value is resolved
+// before the hooks fire, so redaction and hooks see the result, and fn may
+// log itself.
+func Lazy(fn func() interface{}) LazyValue {
+	return LazyValue{fn: fn}
+}
+
+// resolveLazy replaces the lazy values of e with their results. The data of
+// an entry being fired is a copy owned by logrus, so e is updated in place.
+func resolveLazy(e *logrus.Entry) {
+	for k, v := range e.Data {
+		if l, ok := v.(LazyValue); ok {
+			if l.fn == nil {
+				e.Data[k] = nil
+				continue
+			}
+			e.Data[k] = l.fn()
+		}
+	}
+}
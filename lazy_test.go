@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLazyRedacted(t *testing.T) {
+	_, r := Test(t)
+
+	AddRedactedPatterns(regexp.MustCompile(`tok-[0-9]+`))
+
+	WithField("auth", Lazy(func() interface{} { return "bearer tok-12345" })).Info("request")
+
+	if out := r.Output(); strings.Contains(out, "tok-12345") || !strings.Contains(out, Redacted) {
+		t.Fatalf("output %q, want the lazy value redacted", out)
+	}
+}
+
+func TestLazyLogs(t *testing.T) {
+	_, r := Test(t)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		WithField("state", Lazy(func() interface{} {
+			GetLog().Info("computing state")
+			return "ok"
+		})).Info("state dumped")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("logging from a lazy value deadlocked")
+	}
+	if out := r.Output(); !strings.Contains(out, "computing state") || !strings.Contains(out, `"state":"ok"`) {
+		t.Fatalf("output %q, want both entries", out)
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// SetLevel changes the level of the package logger at runtime. When the
+// pretty print flag is derived from the level (Config.PrettyPrint is nil) the
+// formatter is rebuilt so that it follows the new level. Module levels set
+// with SetModuleLevel are kept.
+func SetLevel(level string) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	conf.Level = level
+
+	p := stdPipeline()
+	p.setLevel(lvl)
+	log.SetLevel(p.loggerLevel())
+	if conf.PrettyPrint == nil && !p.custom {
+		f, err := newFormatter(conf, lvl)
+		if err != nil {
+			return err
+		}
+		p.setFormatter(f)
+	}
+
+	return nil
+}
+
+// GetLevel returns the current level of the package logger, not taking the
+// module levels into account.
+func GetLevel() string {
+	p := std.Load()
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.level.String()
+}
+
+// LevelEnabled reports whether entries at level are emitted by the package
+// logger, not taking the module levels into account. It lets callers skip
+// building expensive fields, see also Lazy.
+func LevelEnabled(level logrus.Level) bool {
+	p := std.Load()
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return level <= p.level
+}
@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"testing"
+)
+
+func TestLevelEnabled(t *testing.T) {
+	if err := SetLevel("warn"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetLevel("info") })
+
+	if LevelEnabled(logrus.InfoLevel) || !LevelEnabled(logrus.WarnLevel) {
+		t.Fatal("LevelEnabled does not follow SetLevel")
+	}
+	if got := GetLevel(); got != "warning" {
+		t.Fatalf("level = %q, want warning", got)
+	}
+}
+
+func BenchmarkLevelEnabled(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			LevelEnabled(logrus.DebugLevel)
+		}
+	})
+}
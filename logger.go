@@ -1,41 +1,412 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
 	"github.com/sirupsen/logrus"
+	"io"
 	golog "log"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 )
 
-const EnvLogLevel = "LOG_LEVEL"
+const (
+	EnvLogLevel  = "LOG_LEVEL"
+	EnvLogFormat = "LOG_FORMAT"
+	EnvLogPreset = "LOG_PRESET"
 
-var log *logrus.Logger
+	EnvLogTimestampFormat = "LOG_TIMESTAMP_FORMAT"
+	EnvLogPretty          = "LOG_PRETTY"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatText    = "text"
+	FormatLogfmt  = "logfmt"
+	FormatConsole = "console"
+)
+
+const (
+	PresetGCP = "gcp"
+	PresetECS = "ecs"
+)
+
+const (
+	defaultLevel  = "info"
+	defaultFormat = FormatJSON
+)
+
+// log is written with both mu and logMu held, so it can be read holding
+// either of them.
+var (
+	mu    sync.Mutex
+	logMu sync.RWMutex
+	log   *logrus.Logger
+	conf  Config
+
+	defaultLog *logrus.Logger
+)
+
+var errCustomFormatter = errors.New("logger: the formatter of the logger set with SetLogger cannot be changed")
+
+// Config describes an independently configured logger. Zero values fall back
+// to the package defaults: info level, stdout, RFC3339Nano timestamps and the
+// timestamp/severity/message field names.
+type Config struct {
+	Level string
+	// ModuleLevels maps the names given to Named to their own levels.
+	ModuleLevels map[string]string
+	// Format is one of FormatJSON, FormatText, FormatLogfmt or FormatConsole.
+	Format string
+	// Preset selects a vendor specific formatter, PresetGCP or PresetECS.
+	// It takes precedence over Format, FieldMap and TimestampFormat.
+	Preset string
+	Output io.Writer
+	// PrettyPrint indents the JSON output. When nil it is enabled at debug
+	// level; trace keeps compact lines unless it is set.
+	PrettyPrint *bool
+	// TimestampFormat is a time layout, or one of TimestampUnix,
+	// TimestampUnixMilli, TimestampUnixMicro and TimestampUnixNano for a
+	// numeric timestamp.
+	TimestampFormat string
+	// FieldMap renames the logrus built-in keys ("time", "level", "msg",
+	// "logrus_error", "func", "file") and is merged over the defaults.
+	FieldMap map[string]string
+	// ReportCaller adds the calling function and its file:line to every entry.
+	ReportCaller bool
+	// Fields are added to every entry not already having them.
+	Fields logrus.Fields
+}
 
 func init() {
-	level := os.Getenv(EnvLogLevel)
-	if level == "" {
-		level = "info"
+	cfg, warnings := envConfig()
+
+	var err error
+	if log, err = New(cfg); err != nil {
+		golog.Fatal(err)
+	}
+	defaultLog = log
+	conf = cfg
+	stdPipeline()
+	log.ExitFunc = exit
+	setOutput(cfg.Output)
+	logFile = os.Getenv(EnvLogFile)
+	fields = envFields()
+
+	for _, w := range warnings {
+		log.Warn(w)
+	}
+}
+
+// New returns a logger configured by the options, applied in order. It never
+// touches the package logger returned by GetLog. A Config is an Option
+// replacing the whole configuration, so New(cfg) and New(FromEnv()...) are
+// valid as well. Its Out wraps the configured output, which never sees the
+// entries dropped by module levels, filters or sampling.
+func New(opts ...Option) (*logrus.Logger, error) {
+	var cfg Config
+	for _, opt := range opts {
+		opt.apply(&cfg)
 	}
+	cfg = cfg.withDefaults()
 
-	lvl, err := logrus.ParseLevel(level)
+	lvl, err := logrus.ParseLevel(cfg.Level)
 	if err != nil {
-		golog.Fatal(err)
+		return nil, err
 	}
 
-	log = logrus.New()
-	log.Level = lvl
-	log.Formatter = &logrus.JSONFormatter{
-		FieldMap: logrus.FieldMap{
-			logrus.FieldKeyTime:  "timestamp",
-			logrus.FieldKeyLevel: "severity",
-			logrus.FieldKeyMsg:   "message",
-		},
-		TimestampFormat: time.RFC3339Nano,
-		PrettyPrint:     lvl >= logrus.DebugLevel,
+	levels, err := parseModuleLevels(cfg.ModuleLevels)
+	if err != nil {
+		return nil, err
 	}
-	log.Out = os.Stdout
+
+	f, err := newFormatter(cfg, lvl)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPipeline(f, lvl)
+	p.setModuleLevels(levels)
+	p.conf = &cfg
+	if len(cfg.Fields) > 0 {
+		p.addHook(newFieldsHook(cfg.Fields))
+	}
+
+	l := logrus.New()
+	l.Level = p.loggerLevel()
+	l.Formatter = p
+	l.Out = &errorWriter{w: cfg.Output}
+	l.ReportCaller = cfg.ReportCaller
+	l.AddHook(p)
+
+	return l, nil
 }
 
+// GetLog returns the package logger, which is configured like
+// New(FromEnv()...). Its Out wraps the configured output to report write
+// errors, so change the output with SetOutput rather than through Out.
 func GetLog() *logrus.Logger {
+	logMu.RLock()
+	defer logMu.RUnlock()
+
 	return log
 }
+
+// SetLogger replaces the package logger returned by GetLog and used by the
+// package functions with l, e.g. to install a logger fully controlled by a
+// test; SetLogger(DefaultLogger()) or SetLogger(nil) restores the original.
+// It is safe to call while other goroutines log, but code that kept a
+// logger returned earlier by GetLog keeps using that logger.
+//
+// Every logger keeps its own configuration and internal hooks, and its
+// SetAsync buffer is not closed while it is swapped out. The formatter of a
+// logger not created by New is never replaced: SetLevel leaves it as is, and
+// SetPrettyPrint and the -log-format flag return an error.
+//
+// l itself is changed so that the package functions apply to it: unless it
+// was created by New, the first of them wraps its formatter in the pipeline
+// of this package, which is also added as its first hook. l keeps both once
+// swapped out. A formatter set on l later is wrapped by the next package
+// function, without adding the hook again.
+func SetLogger(l *logrus.Logger) {
+	if l == nil {
+		l = defaultLog
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	saved := conf
+	old := stdPipeline()
+	old.conf, old.async = &saved, asyncOut
+
+	log = l
+	p := stdPipeline()
+	asyncOut = p.async
+	switch {
+	case p.conf != nil:
+		conf = *p.conf
+	default:
+		conf = Config{
+			Level:        l.GetLevel().String(),
+			Output:       l.Out,
+			ReportCaller: l.ReportCaller,
+		}
+	}
+}
+
+// DefaultLogger returns the package logger created from the environment,
+// whatever logger SetLogger installed.
+func DefaultLogger() *logrus.Logger {
+	return defaultLog
+}
+
+// SetPrettyPrint enables or disables indented JSON on the package logger,
+// regardless of its level.
+func SetPrettyPrint(enabled bool) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	p := stdPipeline()
+	if p.custom {
+		return errCustomFormatter
+	}
+	conf.PrettyPrint = &enabled
+
+	p.mu.RLock()
+	lvl := p.level
+	p.mu.RUnlock()
+
+	f, err := newFormatter(conf, lvl)
+	if err != nil {
+		return err
+	}
+	p.setFormatter(f)
+
+	return nil
+}
+
+func (c Config) apply(dst *Config) {
+	*dst = c
+}
+
+func (c Config) withDefaults() Config {
+	if c.Level == "" {
+		c.Level = defaultLevel
+	}
+	if c.Format == "" {
+		c.Format = defaultFormat
+	}
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
+	if c.TimestampFormat == "" {
+		c.TimestampFormat = time.RFC3339Nano
+	}
+	return c
+}
+
+func newFormatter(cfg Config, lvl logrus.Level) (logrus.Formatter, error) {
+	// Trace is meant for firehose output, keep it one entry per line.
+	prettyPrint := lvl == logrus.DebugLevel
+	if cfg.PrettyPrint != nil {
+		prettyPrint = *cfg.PrettyPrint
+	}
+
+	switch cfg.Preset {
+	case "":
+	case PresetGCP:
+		f := NewGCPFormatter()
+		f.PrettyPrint = prettyPrint
+		return f, nil
+	case PresetECS:
+		f := NewECSFormatter()
+		f.PrettyPrint = prettyPrint
+		return f, nil
+	default:
+		return nil, fmt.Errorf("not a valid log preset: %q", cfg.Preset)
+	}
+
+	if cfg.Format == FormatConsole {
+		return NewConsoleFormatter(cfg.Output), nil
+	}
+
+	fm := fieldMap(cfg.FieldMap)
+	timeKey := fm[logrus.FieldKeyTime]
+	unit, epoch := epochUnits[cfg.TimestampFormat]
+	if epoch {
+		// The epoch formatter sets the timestamp itself, keep logrus from
+		// treating it as a clashing field.
+		fm[logrus.FieldKeyTime] = epochTimeKey
+	}
+
+	var f logrus.Formatter
+	switch cfg.Format {
+	case FormatJSON:
+		f = &logrus.JSONFormatter{
+			FieldMap:         fm,
+			TimestampFormat:  cfg.TimestampFormat,
+			DisableTimestamp: epoch,
+			PrettyPrint:      prettyPrint,
+		}
+	case FormatText, FormatLogfmt:
+		// Colors are only used for text and only when the output is a
+		// terminal. logrus only detects one when the logger output is the
+		// *os.File itself, not the writer wrapping it, so check the
+		// configured output.
+		f = &logrus.TextFormatter{
+			FieldMap:         fm,
+			TimestampFormat:  cfg.TimestampFormat,
+			FullTimestamp:    true,
+			DisableTimestamp: epoch,
+			DisableColors:    cfg.Format == FormatLogfmt,
+			ForceColors:      cfg.Format == FormatText && isTerminal(cfg.Output),
+		}
+	default:
+		return nil, fmt.Errorf("not a valid log format: %q", cfg.Format)
+	}
+
+	if epoch {
+		f = &epochFormatter{Formatter: f, key: timeKey, unit: unit}
+	}
+	return f, nil
+}
+
+func validPreset(preset string) bool {
+	switch preset {
+	case "", PresetGCP, PresetECS:
+		return true
+	}
+	return false
+}
+
+func validFormat(format string) bool {
+	switch format {
+	case FormatJSON, FormatText, FormatLogfmt, FormatConsole:
+		return true
+	}
+	return false
+}
+
+func fallback(env, value, def string) string {
+	return fmt.Sprintf("invalid %s %q, falling back to %q", env, value, def)
+}
+
+// envConfig reads the configuration from the environment, replacing invalid
+// values with the defaults and describing each replacement in a warning.
+func envConfig() (Config, []string) {
+	cfg := Config{
+		Level:  os.Getenv(EnvLogLevel),
+		Format: os.Getenv(EnvLogFormat),
+		Preset: os.Getenv(EnvLogPreset),
+
+		TimestampFormat: os.Getenv(EnvLogTimestampFormat),
+	}.withDefaults()
+
+	var warnings []string
+	if _, err := logrus.ParseLevel(cfg.Level); err != nil {
+		warnings = append(warnings, fallback(EnvLogLevel, cfg.Level, defaultLevel))
+		cfg.Level = defaultLevel
+	}
+	if v := os.Getenv(EnvLogLevels); v != "" {
+		var w []string
+		cfg.ModuleLevels, w = envModuleLevels(v)
+		warnings = append(warnings, w...)
+	}
+	if !validFormat(cfg.Format) {
+		warnings = append(warnings, fallback(EnvLogFormat, cfg.Format, defaultFormat))
+		cfg.Format = defaultFormat
+	}
+	if !validPreset(cfg.Preset) {
+		warnings = append(warnings, fallback(EnvLogPreset, cfg.Preset, ""))
+		cfg.Preset = ""
+	}
+	if v := os.Getenv(EnvLogPretty); v != "" {
+		if pretty, err := strconv.ParseBool(v); err != nil {
+			warnings = append(warnings, fallback(EnvLogPretty, v, "level based"))
+		} else {
+			cfg.PrettyPrint = &pretty
+		}
+	}
+	if v := os.Getenv(EnvLogReportCaller); v != "" {
+		var err error
+		if cfg.ReportCaller, err = strconv.ParseBool(v); err != nil {
+			warnings = append(warnings, fallback(EnvLogReportCaller, v, "false"))
+		}
+	}
+	if v := os.Getenv(EnvLogRuntimeMeta); v != "" {
+		if meta, err := strconv.ParseBool(v); err != nil {
+			warnings = append(warnings, fallback(EnvLogRuntimeMeta, v, "false"))
+		} else if meta {
+			WithRuntimeMetadata().apply(&cfg)
+		}
+	}
+	if path := os.Getenv(EnvLogFile); path != "" {
+		opts, w := envRotateOptions(path)
+		warnings = append(warnings, w...)
+		cfg.Output = opts.writer()
+	}
+
+	return cfg, warnings
+}
+
+func fieldMap(m map[string]string) logrus.FieldMap {
+	fm := logrus.FieldMap{
+		logrus.FieldKeyTime:        "timestamp",
+		logrus.FieldKeyLevel:       "severity",
+		logrus.FieldKeyMsg:         "message",
+		logrus.FieldKeyLogrusError: string(logrus.FieldKeyLogrusError),
+		logrus.FieldKeyFunc:        "function",
+		logrus.FieldKeyFile:        "caller",
+	}
+	for k := range fm {
+		if v, ok := m[string(k)]; ok {
+			fm[k] = v
+		}
+	}
+	return fm
+}
@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"testing"
+)
+
+func TestEnvConfigInvalidLevel(t *testing.T) {
+	t.Setenv(EnvLogLevel, "garbage")
+
+	cfg, warnings := envConfig()
+	if cfg.Level != defaultLevel {
+		t.Fatalf("level = %q, want %q", cfg.Level, defaultLevel)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], EnvLogLevel) {
+		t.Fatalf("warnings = %q, want one about %s", warnings, EnvLogLevel)
+	}
+
+	var buf bytes.Buffer
+	cfg.Output = &buf
+	l, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.GetLevel() != logrus.InfoLevel {
+		t.Fatalf("logger level = %s, want info", l.GetLevel())
+	}
+
+	l.Debug("hidden")
+	l.Info("shown")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output %q: %v", buf.String(), err)
+	}
+	if got["message"] != "shown" {
+		t.Fatalf("message = %v, want shown", got["message"])
+	}
+}
+
+func TestEnvConfigPrettyDisabledAtDebug(t *testing.T) {
+	t.Setenv(EnvLogLevel, "debug")
+	t.Setenv(EnvLogPretty, "false")
+
+	cfg, warnings := envConfig()
+	if len(warnings) > 0 {
+		t.Fatalf("warnings = %q", warnings)
+	}
+
+	var buf bytes.Buffer
+	cfg.Output = &buf
+	l, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.WithField("user_id", 42).Debug("created")
+
+	out := strings.TrimSuffix(buf.String(), "\n")
+	if strings.Contains(out, "\n") {
+		t.Fatalf("output %q spans several lines", out)
+	}
+	if !json.Valid([]byte(out)) {
+		t.Fatalf("output %q is not JSON", out)
+	}
+}
+
+func TestEnvConfigTrace(t *testing.T) {
+	t.Setenv(EnvLogLevel, "trace")
+
+	cfg, warnings := envConfig()
+	if len(warnings) > 0 {
+		t.Fatalf("warnings = %q", warnings)
+	}
+
+	var buf bytes.Buffer
+	cfg.Output = &buf
+	l, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.WithField("step", 1).Trace("traced")
+
+	out := strings.TrimSuffix(buf.String(), "\n")
+	if strings.Contains(out, "\n") {
+		t.Fatalf("output %q spans several lines", out)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output %q: %v", out, err)
+	}
+	if got["severity"] != "trace" || got["message"] != "traced" {
+		t.Fatalf("output %q, want a trace entry", out)
+	}
+}
+
+func TestSetLoggerKeepsFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.Out = &buf
+	l.Formatter = &logrus.TextFormatter{DisableColors: true, DisableTimestamp: true}
+
+	before := GetLevel()
+	SetLogger(l)
+	t.Cleanup(func() { SetLogger(nil) })
+
+	if err := SetLevel("debug"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetPrettyPrint(true); err == nil {
+		t.Fatal("SetPrettyPrint changed the formatter of a custom logger")
+	}
+	GetLog().Debug("custom")
+
+	if out := buf.String(); out != "level=debug msg=custom\n" {
+		t.Fatalf("output %q, want text", out)
+	}
+
+	SetLogger(nil)
+	if got := GetLevel(); got != before {
+		t.Fatalf("level after restoring the default logger = %q, want %q", got, before)
+	}
+}
+
+func TestSetLoggerHooks(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(WithOutput(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetLogger(l)
+	t.Cleanup(func() { SetLogger(nil) })
+
+	AddRedactedFields("secret")
+	SetMaxFieldLength(4)
+	SetFlattenFields(true)
+	l.WithFields(logrus.Fields{"secret": "hunter2", "long": "abcdefghij"}).Info("installed")
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "abcdefghij") {
+		t.Fatalf("output %q, want the fields redacted and truncated", out)
+	}
+
+	if _, ok := findHook[*flattenHook](defaultLog.Formatter.(*pipeline)); ok {
+		t.Fatal("SetFlattenFields on the installed logger changed the default one")
+	}
+}
+
+func TestSetLoggerReplacedFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.Out = &buf
+	SetLogger(l)
+	t.Cleanup(func() { SetLogger(nil) })
+
+	AddRedactedFields("token")
+	l.SetFormatter(&logrus.TextFormatter{DisableColors: true, DisableTimestamp: true})
+	AddRedactedFields("password")
+
+	l.WithField("token", "abc").Info("replaced")
+	if out := buf.String(); out != "level=info msg=replaced token=\"***REDACTED***\"\n" {
+		t.Fatalf("output %q, want one redacted text entry", out)
+	}
+	if n := len(l.Hooks[logrus.InfoLevel]); n != 1 {
+		t.Fatalf("%d hooks, want the pipeline once", n)
+	}
+}
@@ -0,0 +1,23 @@
+module github.com/go-funcards/logger/loggrpc
+
+go 1.21
+
+require (
+	github.com/go-funcards/logger v0.0.0-00010101000000-000000000000
+	github.com/sirupsen/logrus v1.9.0
+	google.golang.org/grpc v1.67.3
+)
+
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+)
+
+// Build against the root module of this repository; a release requires its
+// tagged version instead.
+replace github.com/go-funcards/logger => ../
@@ -0,0 +1,97 @@
+// Package loggrpc provides gRPC server interceptors logging through the
+// package logger of github.com/go-funcards/logger.
+package loggrpc
+
+import (
+	"context"
+	"github.com/go-funcards/logger"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+const RequestIDMetadataKey = "x-request-id"
+
+// UnaryServerInterceptor logs one entry per unary call with its method,
+// status code and latency. The handler context carries an entry with the
+// request ID, which handlers retrieve with logger.FromContext.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx, e := withEntry(ctx)
+
+		resp, err := handler(ctx, req)
+		logCall(e, info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor, logging once the stream is finished.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, e := withEntry(ss.Context())
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(e, info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func withEntry(ctx context.Context) (context.Context, *logrus.Entry) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(RequestIDMetadataKey); len(v) > 0 {
+			id = v[0]
+		}
+	}
+	if id == "" {
+		id = logger.NewRequestID()
+	}
+
+	ctx = context.WithValue(ctx, logger.ContextKey("request_id"), id)
+	e := logger.FromContext(ctx)
+
+	return logger.ContextWithLogger(ctx, e), e
+}
+
+func logCall(e *logrus.Entry, method string, start time.Time, err error) {
+	code := status.Code(err)
+
+	e = e.WithFields(logrus.Fields{
+		"method":  method,
+		"code":    code.String(),
+		"latency": time.Since(start),
+	})
+	if err != nil {
+		e = e.WithError(err)
+	}
+	e.Log(codeLevel(code), "grpc call")
+}
+
+func codeLevel(code codes.Code) logrus.Level {
+	switch code {
+	case codes.OK, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.Unauthenticated:
+		return logrus.InfoLevel
+	case codes.Canceled, codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return logrus.WarnLevel
+	default:
+		return logrus.ErrorLevel
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,20 @@
+module github.com/go-funcards/logger/logotel
+
+go 1.21
+
+require (
+	github.com/go-funcards/logger v0.0.0-00010101000000-000000000000
+	github.com/sirupsen/logrus v1.9.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+)
+
+// Build against the root module of this repository; a release requires its
+// tagged version instead.
+replace github.com/go-funcards/logger => ../
@@ -0,0 +1,60 @@
+// Package logotel correlates entries of the package logger of
+// github.com/go-funcards/logger with OpenTelemetry spans.
+package logotel
+
+import (
+	"context"
+	"github.com/go-funcards/logger"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithSpan returns logger.FromContext(ctx) with the trace_id and span_id
+// fields, in hex, of the span in ctx when it is valid. The entry keeps ctx,
+// so SpanHook can find the span.
+func WithSpan(ctx context.Context) *logrus.Entry {
+	e := logger.FromContext(ctx)
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return e
+	}
+	return e.WithFields(logrus.Fields{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+	})
+}
+
+// SpanHook records entries at error level or more severe as events on the
+// recording span of the entry context, so errors show up inline in traces.
+type SpanHook struct{}
+
+func NewSpanHook() *SpanHook {
+	return &SpanHook{}
+}
+
+func (h *SpanHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+}
+
+func (h *SpanHook) Fire(e *logrus.Entry) error {
+	if e.Context == nil {
+		return nil
+	}
+	span := trace.SpanFromContext(e.Context)
+	if !span.IsRecording() {
+		return nil
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("log.severity", e.Level.String()),
+		attribute.String("log.message", e.Message),
+	}
+	if err, ok := e.Data[logrus.ErrorKey].(error); ok {
+		span.RecordError(err, trace.WithAttributes(attrs...))
+		return nil
+	}
+	span.AddEvent("log", trace.WithAttributes(attrs...))
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package logprom provides a logrus hook exporting log line counts as
+// Prometheus metrics.
+package logprom
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+type hook struct {
+	counter *prometheus.CounterVec
+}
+
+// NewPrometheusHook returns a hook incrementing log_messages_total{level} for
+// every entry. The counter is registered with reg, or with the default
+// registerer when reg is nil. If an identical counter is already registered,
+// for example by a previous call, that counter is used. Any other
+// registration error panics, as with prometheus.MustRegister.
+func NewPrometheusHook(reg prometheus.Registerer) logrus.Hook {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_messages_total",
+		Help: "Number of log messages by level.",
+	}, []string{"level"})
+
+	if err := reg.Register(counter); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			panic(err)
+		}
+		existing, ok := are.ExistingCollector.(*prometheus.CounterVec)
+		if !ok {
+			panic(err)
+		}
+		counter = existing
+	}
+
+	return &hook{counter: counter}
+}
+
+func (h *hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *hook) Fire(e *logrus.Entry) error {
+	h.counter.WithLabelValues(e.Level.String()).Inc()
+	return nil
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+const EnvLogLevels = "LOG_LEVELS"
+
+const ModuleKey = "module"
+
+// Named returns an entry of the package logger with the default fields and
+// name in the "module" field. Its entries are filtered by the level set for
+// the module with SetModuleLevel or LOG_LEVELS, or by the logger level when
+// the module has none.
+func Named(name string) *logrus.Entry {
+	return entry().WithField(ModuleKey, name)
+}
+
+// SetModuleLevel sets the level of the entries of module name. An empty level
+// removes it, so the module follows the logger level again.
+func SetModuleLevel(name, level string) error {
+	var (
+		lvl logrus.Level
+		err error
+	)
+	if level != "" {
+		if lvl, err = logrus.ParseLevel(level); err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	levels := make(map[string]string, len(conf.ModuleLevels)+1)
+	for k, v := range conf.ModuleLevels {
+		levels[k] = v
+	}
+	if level == "" {
+		delete(levels, name)
+	} else {
+		levels[name] = level
+	}
+	conf.ModuleLevels = levels
+
+	p := stdPipeline()
+	p.setModuleLevel(name, lvl, level != "")
+	log.SetLevel(p.loggerLevel())
+
+	return nil
+}
+
+func parseModuleLevels(m map[string]string) (map[string]logrus.Level, error) {
+	levels := make(map[string]logrus.Level, len(m))
+	for name, level := range m {
+		lvl, err := logrus.ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("module %q: %w", name, err)
+		}
+		levels[name] = lvl
+	}
+	return levels, nil
+}
+
+// envModuleLevels parses "db=debug,http=warn", skipping invalid items.
+func envModuleLevels(v string) (map[string]string, []string) {
+	var warnings []string
+	levels := make(map[string]string)
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, level, ok := strings.Cut(item, "=")
+		if _, err := logrus.ParseLevel(level); !ok || name == "" || err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid %s item %q, ignoring it", EnvLogLevels, item))
+			continue
+		}
+		levels[name] = level
+	}
+	return levels, warnings
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"testing"
+)
+
+type countHook struct {
+	messages []string
+}
+
+func (h *countHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *countHook) Fire(e *logrus.Entry) error {
+	h.messages = append(h.messages, e.Message)
+	return nil
+}
+
+func TestModuleLevelSuppressesHooks(t *testing.T) {
+	_, r := Test(t)
+
+	if err := SetModuleLevel("chatty", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetModuleLevel("chatty", "") })
+
+	h := &countHook{}
+	AddHook(h)
+	t.Cleanup(ClearHooks)
+
+	Named("quiet").Debug("hidden")
+	Named("chatty").Debug("shown")
+
+	if len(h.messages) != 1 || h.messages[0] != "shown" {
+		t.Fatalf("hook saw %q, want only the chatty entry", h.messages)
+	}
+	if n := len(r.Entries()); n != 1 {
+		t.Fatalf("%d entries recorded, want 1", n)
+	}
+}
+
+// writeCounter counts the writes it gets, empty ones included.
+type writeCounter struct {
+	writes int
+	empty  int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.writes++
+	if len(p) == 0 {
+		w.empty++
+	}
+	return len(p), nil
+}
+
+func TestModuleLevelSkipsWrite(t *testing.T) {
+	out := &writeCounter{}
+	l, err := New(WithOutput(out), WithModuleLevel("chatty", "debug"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.WithField(ModuleKey, "quiet").Debug("hidden")
+	l.WithField(ModuleKey, "chatty").Debug("shown")
+
+	if out.writes != 1 || out.empty != 0 {
+		t.Fatalf("%d writes, %d empty, want the chatty entry only", out.writes, out.empty)
+	}
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"io"
+)
+
+// Option configures a logger created by New.
+type Option interface {
+	apply(cfg *Config)
+}
+
+type optionFunc func(cfg *Config)
+
+func (f optionFunc) apply(cfg *Config) {
+	f(cfg)
+}
+
+// FromEnv returns the options of the configuration read from the
+// environment, the configuration of the package logger.
+func FromEnv() []Option {
+	cfg, _ := envConfig()
+	return []Option{cfg}
+}
+
+func WithLevel(level string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Level = level
+	})
+}
+
+func WithModuleLevel(name, level string) Option {
+	return optionFunc(func(cfg *Config) {
+		levels := make(map[string]string, len(cfg.ModuleLevels)+1)
+		for k, v := range cfg.ModuleLevels {
+			levels[k] = v
+		}
+		levels[name] = level
+		cfg.ModuleLevels = levels
+	})
+}
+
+func WithFormat(format string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Format = format
+	})
+}
+
+func WithJSON() Option {
+	return WithFormat(FormatJSON)
+}
+
+func WithText() Option {
+	return WithFormat(FormatText)
+}
+
+func WithPreset(preset string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Preset = preset
+	})
+}
+
+func WithOutput(w io.Writer) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Output = w
+	})
+}
+
+func WithPrettyPrint(enabled bool) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.PrettyPrint = &enabled
+	})
+}
+
+func WithTimestampFormat(format string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.TimestampFormat = format
+	})
+}
+
+func WithFieldMap(m map[string]string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.FieldMap = m
+	})
+}
+
+func WithCaller() Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.ReportCaller = true
+	})
+}
+
+// WithDefaultFields adds fields to every entry of the logger, merged over
+// the fields of previous WithDefaultFields options.
+func WithDefaultFields(fields logrus.Fields) Option {
+	return optionFunc(func(cfg *Config) {
+		merged := make(logrus.Fields, len(cfg.Fields)+len(fields))
+		for k, v := range cfg.Fields {
+			merged[k] = v
+		}
+		for k, v := range fields {
+			merged[k] = v
+		}
+		cfg.Fields = merged
+	})
+}
+
+// WithRuntimeMetadata adds the hostname, pid, container_id and k8s_pod
+// fields to every entry of the logger, see LOG_RUNTIME_META.
+func WithRuntimeMetadata() Option {
+	return WithDefaultFields(runtimeMetadata())
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// SetFieldOrder makes the package logger write the given JSON keys first, in
+// that order, followed by the other keys sorted, e.g.
+// SetFieldOrder([]string{"timestamp", "severity", "message"}). The keys are
+// the ones written, after FieldMap and presets are applied. Text output is
+// not affected. An empty order restores the sorted logrus output.
+func SetFieldOrder(keys []string) {
+	order := append([]string(nil), keys...)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	stdPipeline().setFieldOrder(order)
+}
+
+// orderJSON rewrites the JSON object b with the keys in order first. Output
+// that is not a JSON object, e.g. text, is returned unchanged; indented
+// output stays indented.
+func orderJSON(b []byte, order []string) []byte {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return b
+	}
+
+	keys := make([]string, 0, len(obj))
+	pinned := make(map[string]bool, len(order))
+	for _, k := range order {
+		if _, ok := obj[k]; ok && !pinned[k] {
+			pinned[k] = true
+			keys = append(keys, k)
+		}
+	}
+	rest := make([]string, 0, len(obj)-len(keys))
+	for k := range obj {
+		if !pinned[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	var buf bytes.Buffer
+	buf.Grow(len(b))
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		// Keys were decoded from JSON, they encode without error.
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err := json.Compact(&buf, obj[k]); err != nil {
+			return b
+		}
+	}
+	buf.WriteByte('}')
+
+	if bytes.Count(bytes.TrimSpace(b), []byte("\n")) > 0 {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, buf.Bytes(), "", "  "); err != nil {
+			return b
+		}
+		buf = indented
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
+}
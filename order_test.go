@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestFieldOrderGolden(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	t.Cleanup(func() { SetOutput(os.Stdout) })
+
+	SetFieldOrder([]string{"timestamp", "severity", "message"})
+	t.Cleanup(func() { SetFieldOrder(nil) })
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	GetLog().WithTime(ts).WithFields(map[string]interface{}{
+		"user_id": 42,
+		"action":  "login",
+		"zone":    "eu",
+	}).Info("user logged in")
+
+	golden := filepath.Join("testdata", "field_order.golden")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Fatalf("output\n%s\nwant\n%s", got, want)
+	}
+}
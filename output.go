@@ -0,0 +1,230 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+const DefaultSplitLevel = logrus.ErrorLevel
+
+var errorHandler atomic.Pointer[func(error)]
+
+// leveledWriter is implemented by outputs routing entries by level. The
+// pipeline formatter passes the level of every entry right before logrus
+// writes it. Both happen under the logger mutex, so leveled outputs must not
+// be combined with logrus.Logger.SetNoLock.
+type leveledWriter interface {
+	io.Writer
+	setLevel(level logrus.Level)
+}
+
+// SetOutput redirects the package logger to w. The swap is serialized with
+// the other package setters and with in-flight writes, which logrus performs
+// under the logger mutex. When SetAsync is active, w is written
+// asynchronously as well, once the entries buffered for the previous output
+// are written.
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	setOutput(w)
+}
+
+// setOutput must be called with mu held.
+func setOutput(w io.Writer) {
+	conf.Output = w
+	if old := asyncOut; old != nil {
+		asyncOut = newAsyncWriter(w, old.size)
+		log.SetOutput(&errorWriter{w: asyncOut, pkg: true})
+		old.close()
+		return
+	}
+	log.SetOutput(&errorWriter{w: w, pkg: true})
+}
+
+// Close writes the entries buffered by SetAsync and closes the output of the
+// package logger, such as a file or syslog connection, which is replaced
+// with stdout. It is a no-op for stdout and stderr, so it can be deferred in
+// any program.
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	old, async := conf.Output, asyncOut
+	asyncOut = nil
+	setOutput(os.Stdout)
+	if async != nil {
+		async.close()
+	}
+
+	return closeWriter(old)
+}
+
+// Sync writes the entries buffered by SetAsync and commits the output of the
+// package logger to stable storage when it is a file. It is a no-op for
+// stdout and stderr.
+func Sync() error {
+	if err := Flush(context.Background()); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return syncWriter(conf.Output)
+}
+
+func closeWriter(w io.Writer) error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func syncWriter(w io.Writer) error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	if s, ok := w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// SetLineTerminator sets the bytes ending every entry of the package logger,
+// e.g. []byte{0} or []byte("\r\n"), in place of the newline of the
+// formatters. The terminator is part of the formatted entry, so it is
+// written in the same Write call, asynchronously as well. A nil or empty
+// terminator restores the newline.
+func SetLineTerminator(b []byte) {
+	var terminator []byte
+	if len(b) > 0 {
+		terminator = bytes.Clone(b)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	stdPipeline().setTerminator(terminator)
+}
+
+// SetErrorHandler sets a function called with the errors writing to the
+// output of the package logger, e.g. to count failures or to fall back to
+// another output, and with the field names rejected by the validator set
+// with SetFieldNameValidator. The handler must not log through the package
+// logger. A nil handler restores the default, where logrus reports the
+// error on stderr.
+func SetErrorHandler(handler func(err error)) {
+	if handler == nil {
+		errorHandler.Store(nil)
+		return
+	}
+	errorHandler.Store(&handler)
+}
+
+// writeError passes err to the error handler and returns nil, or returns err
+// when there is no handler.
+func writeError(err error) error {
+	if h := errorHandler.Load(); h != nil {
+		(*h)(err)
+		return nil
+	}
+	return err
+}
+
+// errorWriter is the output of the loggers of this package. It skips the
+// entries dropped by the pipeline, which logrus writes as nothing, and syncs
+// the output after fatal and panic entries.
+type errorWriter struct {
+	w     io.Writer
+	level logrus.Level
+	// pkg is set on the output of the package logger, whose writes count in
+	// Stats and whose errors go through writeError. The entries written
+	// through the SetAsync buffer are counted by asyncWriter.
+	pkg bool
+}
+
+func (w *errorWriter) setLevel(level logrus.Level) {
+	w.level = level
+	if lw, ok := w.w.(leveledWriter); ok {
+		lw.setLevel(level)
+	}
+}
+
+func (w *errorWriter) Write(p []byte) (int, error) {
+	// A dropped fatal or panic entry still syncs the output.
+	if len(p) > 0 {
+		n, err := w.w.Write(p)
+		if err != nil {
+			if !w.pkg {
+				return n, err
+			}
+			counters.writeErrors.Add(1)
+			return n, writeError(err)
+		}
+		if _, async := w.w.(*asyncWriter); w.pkg && !async {
+			counters.written.Add(1)
+		}
+	}
+	if w.level <= logrus.FatalLevel {
+		_ = syncWriter(w.w)
+	}
+	return len(p), nil
+}
+
+// reportWriteError reports an error of a write logrus does not see.
+func reportWriteError(err error) {
+	counters.writeErrors.Add(1)
+	if err = writeError(err); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+	}
+}
+
+// SetLevelSplitOutput writes entries at threshold or more severe to stderr
+// and all others to stdout. Nil writers default to os.Stdout and os.Stderr,
+// DefaultSplitLevel sends errors, fatals and panics to stderr.
+func SetLevelSplitOutput(stdout, stderr io.Writer, threshold logrus.Level) {
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	SetOutput(&splitWriter{low: stdout, high: stderr, threshold: threshold})
+}
+
+type splitWriter struct {
+	low       io.Writer
+	high      io.Writer
+	threshold logrus.Level
+	level     logrus.Level
+}
+
+func (w *splitWriter) setLevel(level logrus.Level) {
+	w.level = level
+}
+
+func (w *splitWriter) Write(p []byte) (int, error) {
+	if w.level <= w.threshold {
+		return w.high.Write(p)
+	}
+	return w.low.Write(p)
+}
+
+func (w *splitWriter) Close() error {
+	return errors.Join(closeWriter(w.low), closeWriter(w.high))
+}
+
+func (w *splitWriter) Sync() error {
+	return errors.Join(syncWriter(w.low), syncWriter(w.high))
+}
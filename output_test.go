@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestSetOutput(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	t.Cleanup(func() { SetOutput(os.Stdout) })
+
+	WithField("user_id", 42).Info("created")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output %q: %v", buf.String(), err)
+	}
+	if got["message"] != "created" {
+		t.Errorf("message = %v, want created", got["message"])
+	}
+	if got["severity"] != "info" {
+		t.Errorf("severity = %v, want info", got["severity"])
+	}
+	if got["user_id"] != float64(42) {
+		t.Errorf("user_id = %v, want 42", got["user_id"])
+	}
+}
@@ -0,0 +1,323 @@
+package logger
+
+import (
+	"bytes"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"sync/atomic"
+)
+
+// pipeline holds the per-logger processing configured by the package
+// functions. It is both the logger formatter, wrapping the one built from the
+// Config, and the first logger hook, running the internal hooks before any
+// hook added by the user. Entries dropped by the formatter side, e.g. by
+// sampling, are formatted as nothing.
+type pipeline struct {
+	mu      sync.RWMutex
+	inner   logrus.Formatter
+	hooks   []logrus.Hook
+	sampler *sampler
+	filters []func(*logrus.Entry) bool
+	// order lists the JSON keys written first, see SetFieldOrder.
+	order []string
+	// terminator replaces the newline ending every entry when set.
+	terminator []byte
+	// level is the level of the logger, levels the levels of its modules.
+	// The logrus level is the most verbose of them.
+	level  logrus.Level
+	levels map[string]logrus.Level
+	// conf is the configuration of the logger and async its SetAsync
+	// output, kept while another logger is installed by SetLogger. Both are
+	// guarded by mu.
+	conf  *Config
+	async *asyncWriter
+	// custom is set when inner is a formatter of the user, which the package
+	// functions never replace.
+	custom bool
+}
+
+func newPipeline(f logrus.Formatter, level logrus.Level) *pipeline {
+	return &pipeline{
+		inner: f,
+		hooks: []logrus.Hook{callerHook{}},
+		level: level,
+	}
+}
+
+func (p *pipeline) Format(e *logrus.Entry) ([]byte, error) {
+	// Set the level before the entry may be dropped: logrus writes the
+	// empty result as well.
+	if w, ok := e.Logger.Out.(leveledWriter); ok {
+		w.setLevel(e.Level)
+	}
+
+	p.mu.RLock()
+	inner, s, order := p.inner, p.sampler, p.order
+	filters, terminator := p.filters, p.terminator
+	enabled := p.enabled(e)
+	p.mu.RUnlock()
+
+	if !enabled {
+		return nil, nil
+	}
+	for _, keep := range filters {
+		if !keep(e) {
+			counters.filtered.Add(1)
+			return nil, nil
+		}
+	}
+	if s != nil && !s.sample(e) {
+		return nil, nil
+	}
+
+	b, err := inner.Format(e)
+	if err != nil {
+		return nil, err
+	}
+	if len(order) > 0 {
+		b = orderJSON(b, order)
+	}
+	if terminator != nil {
+		b = append(bytes.TrimSuffix(b, []byte("\n")), terminator...)
+	}
+	return b, nil
+}
+
+func (p *pipeline) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (p *pipeline) Fire(e *logrus.Entry) error {
+	p.mu.RLock()
+	hooks := p.hooks
+	enabled := p.enabled(e)
+	p.mu.RUnlock()
+
+	// Entries below the level of their module stop here, before any hook
+	// added with AddHook sees them.
+	if !enabled {
+		return nil
+	}
+	// Resolve the lazy values before the hooks read the fields. logrus holds
+	// no lock while firing hooks, so fn may log as well.
+	resolveLazy(e)
+
+	for _, h := range hooks {
+		for _, lvl := range h.Levels() {
+			if lvl != e.Level {
+				continue
+			}
+			if err := h.Fire(e); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// enabled reports whether the entry passes the level of its module, or of
+// the logger. It must be called with p.mu held.
+func (p *pipeline) enabled(e *logrus.Entry) bool {
+	if len(p.levels) == 0 {
+		return true
+	}
+
+	level := p.level
+	if name, ok := e.Data[ModuleKey].(string); ok {
+		if lvl, ok := p.levels[name]; ok {
+			level = lvl
+		}
+	}
+	return e.Level <= level
+}
+
+func (p *pipeline) setLevel(level logrus.Level) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.level = level
+}
+
+func (p *pipeline) setModuleLevels(levels map[string]logrus.Level) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.levels = levels
+}
+
+func (p *pipeline) setModuleLevel(name string, level logrus.Level, set bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	levels := make(map[string]logrus.Level, len(p.levels)+1)
+	for k, v := range p.levels {
+		levels[k] = v
+	}
+	if set {
+		levels[name] = level
+	} else {
+		delete(levels, name)
+	}
+	p.levels = levels
+}
+
+// loggerLevel returns the level logrus must use to let through the entries
+// of the logger and of all its modules.
+func (p *pipeline) loggerLevel() logrus.Level {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	level := p.level
+	for _, lvl := range p.levels {
+		if lvl > level {
+			level = lvl
+		}
+	}
+	return level
+}
+
+func (p *pipeline) setFormatter(f logrus.Formatter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inner = f
+}
+
+func (p *pipeline) setFieldOrder(order []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.order = order
+}
+
+func (p *pipeline) setTerminator(terminator []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.terminator = terminator
+}
+
+func (p *pipeline) addFilter(keep func(*logrus.Entry) bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.filters = append(p.filters[:len(p.filters):len(p.filters)], keep)
+}
+
+// addHook adds h after the hooks of the same or a lower rank.
+func (p *pipeline) addHook(h logrus.Hook) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rank := hookRank(h)
+	i := len(p.hooks)
+	for i > 0 && hookRank(p.hooks[i-1]) > rank {
+		i--
+	}
+
+	hooks := make([]logrus.Hook, 0, len(p.hooks)+1)
+	hooks = append(hooks, p.hooks[:i]...)
+	hooks = append(hooks, h)
+	p.hooks = append(hooks, p.hooks[i:]...)
+}
+
+// hookRank gives the internal hooks a fixed order, whatever order they are
+// enabled in: the fields are completed and reshaped before redaction looks
+// at them, and truncated after, so a cut never hides a secret from a
+// pattern. Other hooks run after them, in the order they were added.
+func hookRank(h logrus.Hook) int {
+	switch h.(type) {
+	case callerHook:
+		return 0
+	case *fieldsHook:
+		return 1
+	case *flattenHook:
+		return 2
+	case *durationHook:
+		return 3
+	case *redactHook:
+		return 4
+	case *truncateHook:
+		return 5
+	default:
+		return 10
+	}
+}
+
+// findHook returns the hook of type H of p, if it has one.
+func findHook[H logrus.Hook](p *pipeline) (H, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, h := range p.hooks {
+		if h, ok := h.(H); ok {
+			return h, true
+		}
+	}
+	var zero H
+	return zero, false
+}
+
+// removeUserHooks removes the hooks added with AddHook.
+func (p *pipeline) removeUserHooks() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	hooks := make([]logrus.Hook, 0, len(p.hooks))
+	for _, h := range p.hooks {
+		if _, ok := h.(userHook); !ok {
+			hooks = append(hooks, h)
+		}
+	}
+	p.hooks = hooks
+}
+
+func (p *pipeline) removeHook(h logrus.Hook) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	hooks := make([]logrus.Hook, 0, len(p.hooks))
+	for _, ph := range p.hooks {
+		if ph != h {
+			hooks = append(hooks, ph)
+		}
+	}
+	p.hooks = hooks
+}
+
+// std is the pipeline last returned by stdPipeline, for the getters reading
+// the level without mu.
+var std atomic.Pointer[pipeline]
+
+// stdPipeline returns the pipeline of the package logger, installing one
+// around its formatter if it was replaced through GetLog or the logger was
+// not created by New. It must be called with mu held.
+func stdPipeline() *pipeline {
+	p := logPipeline()
+	std.Store(p)
+	return p
+}
+
+func logPipeline() *pipeline {
+	if p, ok := log.Formatter.(*pipeline); ok {
+		return p
+	}
+
+	// Wrap a replaced formatter in the pipeline already hooked, so that it
+	// never fires twice.
+	for _, h := range log.Hooks[logrus.PanicLevel] {
+		if p, ok := h.(*pipeline); ok {
+			p.setFormatter(log.Formatter)
+			p.custom = true
+			log.SetFormatter(p)
+			return p
+		}
+	}
+
+	p := newPipeline(log.Formatter, log.GetLevel())
+	p.custom = true
+	log.SetFormatter(p)
+	log.AddHook(p)
+	return p
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+// maxPooledFields keeps entries that grew unusually large out of the pool.
+const maxPooledFields = 64
+
+var entryPool = sync.Pool{
+	New: func() interface{} {
+		return &logrus.Entry{Data: make(logrus.Fields, 8)}
+	},
+}
+
+// Acquire returns an entry of the package logger with the default fields,
+// reusing the entry and fields map of a released one. It saves only the map
+// WithFields allocates, one allocation and a few hundred bytes per entry:
+// logrus still copies the entry and its fields to log it, and formatting
+// dominates the cost. Fields are set on its Data directly before logging:
+//
+//	e := logger.Acquire()
+//	e.Data["path"] = r.URL.Path
+//	e.Info("request")
+//	logger.Release(e)
+//
+// An acquired entry belongs to the calling goroutine until it is released.
+// Entries derived from it, e.g. with WithField, are not pooled.
+func Acquire() *logrus.Entry {
+	e := entryPool.Get().(*logrus.Entry)
+	e.Logger = GetLog()
+
+	fieldsMu.RLock()
+	defer fieldsMu.RUnlock()
+
+	for k, v := range fields {
+		e.Data[k] = v
+	}
+	return e
+}
+
+// Release clears e and returns it to the pool used by Acquire. e must not be
+// used afterwards.
+func Release(e *logrus.Entry) {
+	if e == nil || len(e.Data) > maxPooledFields {
+		return
+	}
+
+	data := e.Data
+	clear(data)
+	*e = logrus.Entry{Data: data}
+	entryPool.Put(e)
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestAcquireConcurrent(t *testing.T) {
+	_, r := Test(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				e := Acquire()
+				e.Data["worker"] = i
+				e.Data["n"] = j
+				e.Info("acquired")
+				Release(e)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if n := len(r.Entries()); n != 800 {
+		t.Fatalf("%d entries, want 800", n)
+	}
+	for _, e := range r.Entries() {
+		if _, ok := e.Data["worker"]; !ok {
+			t.Fatalf("entry %v lost its fields", e.Data)
+		}
+	}
+}
+
+func BenchmarkAcquire(b *testing.B) {
+	SetOutput(io.Discard)
+	b.Cleanup(func() { SetOutput(os.Stdout) })
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e := Acquire()
+		e.Data["path"] = "/users"
+		e.Data["status"] = 200
+		e.Info("request")
+		Release(e)
+	}
+}
+
+func BenchmarkWithFields(b *testing.B) {
+	SetOutput(io.Discard)
+	b.Cleanup(func() { SetOutput(os.Stdout) })
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		GetLog().WithFields(logrus.Fields{"path": "/users", "status": 200}).Info("request")
+	}
+}
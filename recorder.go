@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"bytes"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Test redirects the package logger into a Recorder for the duration of the
+// test and restores the previous output in t.Cleanup. As it changes the
+// package logger, it must not be used by parallel tests.
+func Test(t testing.TB) (*logrus.Logger, *Recorder) {
+	t.Helper()
+
+	r := &Recorder{}
+
+	mu.Lock()
+	l := log
+	prev := conf.Output
+	setOutput(r)
+	p := stdPipeline()
+	p.addHook(r)
+	mu.Unlock()
+
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		p.removeHook(r)
+		setOutput(prev)
+	})
+
+	return l, r
+}
+
+// Recorder keeps the entries logged and the formatted output.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []*logrus.Entry
+	out     bytes.Buffer
+}
+
+func (r *Recorder) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (r *Recorder) Fire(e *logrus.Entry) error {
+	c := *e
+	c.Data = make(logrus.Fields, len(e.Data))
+	for k, v := range e.Data {
+		c.Data[k] = v
+	}
+	c.Buffer = nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, &c)
+	return nil
+}
+
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.out.Write(p)
+}
+
+// Entries returns the recorded entries, oldest first.
+func (r *Recorder) Entries() []*logrus.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*logrus.Entry(nil), r.entries...)
+}
+
+// LastEntry returns the most recent entry, or nil.
+func (r *Recorder) LastEntry() *logrus.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.entries) == 0 {
+		return nil
+	}
+	return r.entries[len(r.entries)-1]
+}
+
+// Contains reports whether an entry was logged at level with a message
+// containing substr.
+func (r *Recorder) Contains(level logrus.Level, substr string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.entries {
+		if e.Level == level && strings.Contains(e.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Output returns the formatted output.
+func (r *Recorder) Output() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.out.String()
+}
+
+// Reset discards the recorded entries and output.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = nil
+	r.out.Reset()
+}
@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const Redacted = "***REDACTED***"
+
+// AddRedactedFields replaces the values of the given field keys with Redacted
+// on the package logger. Keys are matched case-insensitively, also inside
+// nested field maps and against the last segment of dotted keys, such as
+// those written by SetFlattenFields.
+func AddRedactedFields(keys ...string) {
+	h := redactHookOf()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, k := range keys {
+		h.keys[strings.ToLower(k)] = true
+	}
+}
+
+// AddRedactedPatterns replaces the parts of string field values matching any
+// of the patterns with Redacted on the package logger.
+func AddRedactedPatterns(patterns ...*regexp.Regexp) {
+	h := redactHookOf()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.patterns = append(h.patterns, patterns...)
+}
+
+func redactHookOf() *redactHook {
+	mu.Lock()
+	defer mu.Unlock()
+
+	p := stdPipeline()
+	h, ok := findHook[*redactHook](p)
+	if !ok {
+		h = &redactHook{keys: make(map[string]bool)}
+		p.addHook(h)
+	}
+	return h
+}
+
+// redactHook rewrites entry fields before they are formatted. logrus hands
+// hooks a copy of the entry data, and nested maps are copied before they are
+// changed, so the maps passed by callers are never modified.
+type redactHook struct {
+	mu       sync.RWMutex
+	keys     map[string]bool
+	patterns []*regexp.Regexp
+}
+
+func (h *redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *redactHook) Fire(e *logrus.Entry) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for k, v := range e.Data {
+		if r, ok := h.redact(k, v); ok {
+			e.Data[k] = r
+		}
+	}
+	return nil
+}
+
+func (h *redactHook) redact(key string, value interface{}) (interface{}, bool) {
+	key = strings.ToLower(key)
+	if h.keys[key] {
+		return Redacted, true
+	}
+	if i := strings.LastIndexByte(key, '.'); i >= 0 && h.keys[key[i+1:]] {
+		return Redacted, true
+	}
+
+	switch v := value.(type) {
+	case string:
+		changed := false
+		for _, p := range h.patterns {
+			if p.MatchString(v) {
+				v = p.ReplaceAllString(v, Redacted)
+				changed = true
+			}
+		}
+		return v, changed
+	case logrus.Fields:
+		if m, ok := h.redactMap(v); ok {
+			return logrus.Fields(m), true
+		}
+	case map[string]interface{}:
+		return h.redactMap(v)
+	}
+	return value, false
+}
+
+func (h *redactHook) redactMap(m map[string]interface{}) (map[string]interface{}, bool) {
+	var out map[string]interface{}
+	for k, v := range m {
+		r, ok := h.redact(k, v)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]interface{}, len(m))
+			for k, v := range m {
+				out[k] = v
+			}
+		}
+		out[k] = r
+	}
+	return out, out != nil
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"os"
+	"os/signal"
+)
+
+// logFile is the LOG_FILE the package logger output was last read from.
+var logFile string
+
+// WatchSignals reloads the environment configuration of the package logger
+// (levels, format, preset, timestamp format, caller reporting and LOG_FILE)
+// whenever one of the signals arrives, and logs what changed. Values applied
+// with ApplyFlags keep precedence over the environment. Without
+// arguments it watches SIGHUP; it is a no-op on platforms without it. The
+// returned function stops watching.
+func WatchSignals(sig ...os.Signal) (stop func()) {
+	if len(sig) == 0 {
+		sig = reloadSignals
+	}
+	if len(sig) == 0 {
+		return func() {}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sig...)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ch:
+				reload()
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+func reload() {
+	cfg, warnings := envConfig()
+	cfg = withFlags(cfg)
+	for _, w := range warnings {
+		GetLog().Warn(w)
+	}
+
+	lvl, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		GetLog().WithError(err).Error("failed to reload logger configuration")
+		return
+	}
+	levels, err := parseModuleLevels(cfg.ModuleLevels)
+	if err != nil {
+		GetLog().WithError(err).Error("failed to reload logger configuration")
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	path := os.Getenv(EnvLogFile)
+	if path == logFile {
+		cfg.Output = conf.Output
+	}
+	if cfg.PrettyPrint == nil {
+		cfg.PrettyPrint = conf.PrettyPrint
+	}
+	cfg.FieldMap = conf.FieldMap
+
+	// The formatter of a logger set with SetLogger is kept as it is.
+	p := stdPipeline()
+	var f logrus.Formatter
+	if p.custom {
+		cfg.Format, cfg.Preset = conf.Format, conf.Preset
+		cfg.TimestampFormat, cfg.PrettyPrint = conf.TimestampFormat, conf.PrettyPrint
+	} else if f, err = newFormatter(cfg, lvl); err != nil {
+		log.WithError(err).Error("failed to reload logger configuration")
+		return
+	}
+
+	changes := logrus.Fields{}
+	diff := func(name string, from, to interface{}) {
+		if from != to {
+			changes[name] = fmt.Sprintf("%v -> %v", from, to)
+		}
+	}
+	diff("level", conf.Level, cfg.Level)
+	diff("levels", fmt.Sprint(conf.ModuleLevels), fmt.Sprint(cfg.ModuleLevels))
+	diff("format", conf.Format, cfg.Format)
+	diff("preset", conf.Preset, cfg.Preset)
+	diff("timestamp_format", conf.TimestampFormat, cfg.TimestampFormat)
+	diff("report_caller", conf.ReportCaller, cfg.ReportCaller)
+	diff("file", logFile, path)
+
+	old := conf.Output
+	conf = cfg
+	conf.Output = old
+	logFile = path
+
+	p.setLevel(lvl)
+	p.setModuleLevels(levels)
+	if f != nil {
+		p.setFormatter(f)
+	}
+	log.SetLevel(p.loggerLevel())
+	log.SetReportCaller(cfg.ReportCaller)
+	if h, ok := findHook[*durationHook](p); ok {
+		setDurationHook(h.enc, cfg.TimestampFormat)
+	}
+	if cfg.Output != old {
+		setOutput(cfg.Output)
+		if err := closeWriter(old); err != nil {
+			log.WithError(err).Warn("failed to close the previous log output")
+		}
+	}
+
+	log.WithFields(changes).Info("logger configuration reloaded")
+}
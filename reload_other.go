@@ -0,0 +1,9 @@
+//go:build !unix
+
+package logger
+
+import (
+	"os"
+)
+
+var reloadSignals []os.Signal
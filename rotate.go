@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
+	"os"
+	"strconv"
+)
+
+const (
+	EnvLogFile       = "LOG_FILE"
+	EnvLogMaxSizeMB  = "LOG_MAX_SIZE_MB"
+	EnvLogMaxBackups = "LOG_MAX_BACKUPS"
+	EnvLogMaxAgeDays = "LOG_MAX_AGE_DAYS"
+)
+
+const (
+	defaultMaxSizeMB  = 100
+	defaultMaxBackups = 0
+	defaultMaxAgeDays = 0
+)
+
+// RotateOptions configures a log file rotated by size. Old files are removed
+// once they exceed MaxBackups or MaxAgeDays; zero keeps them all.
+type RotateOptions struct {
+	Filename   string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
+}
+
+// SetRotatingFileOutput redirects the package logger to a rotating file.
+// Every entry is written with a single Write call and the rotation happens
+// under the writer lock before the write, so an entry never spans two files.
+func SetRotatingFileOutput(opts RotateOptions) {
+	SetOutput(opts.writer())
+}
+
+func (o RotateOptions) writer() io.Writer {
+	return &lumberjack.Logger{
+		Filename:   o.Filename,
+		MaxSize:    o.MaxSizeMB,
+		MaxBackups: o.MaxBackups,
+		MaxAge:     o.MaxAgeDays,
+		Compress:   o.Compress,
+		LocalTime:  true,
+	}
+}
+
+func envRotateOptions(path string) (RotateOptions, []string) {
+	var warnings []string
+	envInt := func(env string, def int) int {
+		v := os.Getenv(env)
+		if v == "" {
+			return def
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			warnings = append(warnings, fallback(env, v, strconv.Itoa(def)))
+			return def
+		}
+		return n
+	}
+
+	return RotateOptions{
+		Filename:   path,
+		MaxSizeMB:  envInt(EnvLogMaxSizeMB, defaultMaxSizeMB),
+		MaxBackups: envInt(EnvLogMaxBackups, defaultMaxBackups),
+		MaxAgeDays: envInt(EnvLogMaxAgeDays, defaultMaxAgeDays),
+	}, warnings
+}
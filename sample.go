@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SampleOptions configures sampling of repeated entries: within every Tick
+// the First entries with the same level and message are logged, after that
+// only every Thereafter-th one.
+type SampleOptions struct {
+	Tick       time.Duration
+	First      int
+	Thereafter int
+	// Report logs a warning with the number of dropped entries after every
+	// Tick in which entries were dropped.
+	Report bool
+}
+
+// SetSampling enables sampling on the package logger. A zero Tick disables
+// it.
+func SetSampling(opts SampleOptions) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	p := stdPipeline()
+
+	var s *sampler
+	if opts.Tick > 0 {
+		s = newSampler(opts)
+		if opts.Report {
+			go s.report(log)
+		}
+	}
+
+	p.mu.Lock()
+	old := p.sampler
+	p.sampler = s
+	p.mu.Unlock()
+
+	if old != nil {
+		close(old.done)
+	}
+}
+
+type sampleKey struct {
+	level   logrus.Level
+	message string
+}
+
+type sampler struct {
+	opts    SampleOptions
+	done    chan struct{}
+	dropped atomic.Uint64
+
+	mu     sync.Mutex
+	reset  time.Time
+	counts map[sampleKey]int
+}
+
+func newSampler(opts SampleOptions) *sampler {
+	return &sampler{
+		opts:   opts,
+		done:   make(chan struct{}),
+		counts: make(map[sampleKey]int),
+	}
+}
+
+func (s *sampler) sample(e *logrus.Entry) bool {
+	s.mu.Lock()
+	if now := time.Now(); now.After(s.reset) {
+		s.reset = now.Add(s.opts.Tick)
+		s.counts = make(map[sampleKey]int)
+	}
+	k := sampleKey{level: e.Level, message: e.Message}
+	n := s.counts[k] + 1
+	s.counts[k] = n
+	s.mu.Unlock()
+
+	if n <= s.opts.First || (s.opts.Thereafter > 0 && (n-s.opts.First)%s.opts.Thereafter == 0) {
+		return true
+	}
+	s.dropped.Add(1)
+	counters.sampled.Add(1)
+	return false
+}
+
+func (s *sampler) report(l *logrus.Logger) {
+	t := time.NewTicker(s.opts.Tick)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-t.C:
+			if n := s.dropped.Swap(0); n > 0 {
+				l.WithField("dropped", n).Warn("log entries dropped by sampling")
+			}
+		}
+	}
+}
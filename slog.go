@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"log/slog"
+)
+
+type slogHandler struct {
+	fields logrus.Fields
+	prefix string
+}
+
+// NewSlogHandler returns a slog.Handler writing through the package logger,
+// so its level, formatter and output apply to slog records as well. Groups
+// are flattened: an attribute "status" inside the group "http" is logged as
+// the field "http.status".
+func NewSlogHandler() slog.Handler {
+	return &slogHandler{}
+}
+
+// NewSlogLogger returns a slog.Logger backed by NewSlogHandler.
+func NewSlogLogger() *slog.Logger {
+	return slog.New(NewSlogHandler())
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return GetLog().IsLevelEnabled(logrusLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	data := make(logrus.Fields, len(h.fields)+r.NumAttrs())
+	for k, v := range h.fields {
+		data[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(data, h.prefix, a)
+		return true
+	})
+
+	e := FromContext(ctx).WithFields(data)
+	if !r.Time.IsZero() {
+		e = e.WithTime(r.Time)
+	}
+	e.Log(logrusLevel(r.Level), r.Message)
+
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	data := make(logrus.Fields, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		data[k] = v
+	}
+	for _, a := range attrs {
+		addAttr(data, h.prefix, a)
+	}
+	return &slogHandler{fields: data, prefix: h.prefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+func addAttr(data logrus.Fields, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			addAttr(data, prefix, ga)
+		}
+		return
+	}
+
+	data[prefix+a.Key] = a.Value.Any()
+}
+
+func logrusLevel(level slog.Level) logrus.Level {
+	switch {
+	case level >= slog.LevelError:
+		return logrus.ErrorLevel
+	case level >= slog.LevelWarn:
+		return logrus.WarnLevel
+	case level >= slog.LevelInfo:
+		return logrus.InfoLevel
+	case level >= slog.LevelDebug:
+		return logrus.DebugLevel
+	default:
+		return logrus.TraceLevel
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"sync/atomic"
+)
+
+// counters back Stats. They are updated by the package logger output and by
+// the outputs of this package wherever an entry is written or lost.
+var counters struct {
+	written     atomic.Uint64
+	dropped     atomic.Uint64
+	sampled     atomic.Uint64
+	filtered    atomic.Uint64
+	writeErrors atomic.Uint64
+}
+
+// LoggerStats is a snapshot of the package logger counters, for alerting
+// when the logger discards output.
+type LoggerStats struct {
+	// Written counts the entries accepted by the output, not the delivered
+	// ones: an entry written to the SetOutputs outputs counts once even when
+	// they all fail, each failure counting in WriteErrors, and a line a
+	// NewHTTPSink fails to send later counts in Dropped as well.
+	Written uint64
+	// Dropped counts the entries lost by the SetAsync buffer when full and
+	// the lines lost by the NewHTTPSink outputs.
+	Dropped uint64
+	// Sampled counts the entries dropped by sampling.
+	Sampled uint64
+	// Filtered counts the entries discarded by the AddFilter filters.
+	Filtered uint64
+	// WriteErrors counts the failed writes, including those of each of the
+	// SetOutputs outputs and of the NewHTTPSink batches.
+	WriteErrors uint64
+	// BufferLen is the number of entries waiting in the SetAsync buffer.
+	BufferLen int
+}
+
+// Stats returns the counters of the package logger since the start of the
+// process. Each counter is read atomically, but the snapshot as a whole is
+// not taken while logging is stopped.
+func Stats() LoggerStats {
+	s := LoggerStats{
+		Written:     counters.written.Load(),
+		Dropped:     counters.dropped.Load(),
+		Sampled:     counters.sampled.Load(),
+		Filtered:    counters.filtered.Load(),
+		WriteErrors: counters.writeErrors.Load(),
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if asyncOut != nil {
+		s.BufferLen = len(asyncOut.queue)
+	}
+	return s
+}
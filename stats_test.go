@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"os"
+	"testing"
+	"time"
+)
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestStats(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	t.Cleanup(func() { SetOutput(os.Stdout) })
+
+	check := func(t *testing.T, before LoggerStats, want LoggerStats) {
+		t.Helper()
+
+		got := Stats()
+		got.Written -= before.Written
+		got.Dropped -= before.Dropped
+		got.Sampled -= before.Sampled
+		got.Filtered -= before.Filtered
+		got.WriteErrors -= before.WriteErrors
+		if got != want {
+			t.Fatalf("stats changed by %+v, want %+v", got, want)
+		}
+	}
+
+	t.Run("filter", func(t *testing.T) {
+		AddFilter(func(e *logrus.Entry) bool { return e.Message != "stats filtered" })
+
+		before := Stats()
+		GetLog().Info("stats filtered")
+		check(t, before, LoggerStats{Filtered: 1})
+	})
+
+	t.Run("sample", func(t *testing.T) {
+		SetSampling(SampleOptions{Tick: time.Hour, First: 1})
+		t.Cleanup(func() { SetSampling(SampleOptions{}) })
+
+		before := Stats()
+		GetLog().Info("stats sampled")
+		GetLog().Info("stats sampled")
+		check(t, before, LoggerStats{Written: 1, Sampled: 1})
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		SetOutput(failWriter{})
+		SetErrorHandler(func(err error) {})
+		t.Cleanup(func() {
+			SetErrorHandler(nil)
+			SetOutput(&buf)
+		})
+
+		before := Stats()
+		GetLog().Info("stats failed")
+		check(t, before, LoggerStats{WriteErrors: 1})
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		out := newGateWriter()
+		SetOutput(out)
+		SetAsync(1)
+		SetOverflowPolicy(OverflowDrop)
+		t.Cleanup(func() {
+			SetOverflowPolicy(OverflowBlock)
+			SetAsync(0)
+			SetOutput(&buf)
+		})
+
+		before := Stats()
+		GetLog().Info("stats 1")
+		<-out.entered
+		GetLog().Info("stats 2")
+		GetLog().Info("stats 3")
+		close(out.gate)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := Flush(ctx); err != nil {
+			t.Fatal(err)
+		}
+		check(t, before, LoggerStats{Written: 2, Dropped: 1})
+	})
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+)
+
+// KVErrorKey holds the complaint about a key given to the *w functions
+// without a value.
+const KVErrorKey = "kv_error"
+
+// Tracew logs msg at trace level with the alternating key/value pairs kv.
+func Tracew(msg string, kv ...interface{}) {
+	logw(logrus.TraceLevel, msg, kv)
+}
+
+// Debugw logs msg at debug level with the alternating key/value pairs kv.
+func Debugw(msg string, kv ...interface{}) {
+	logw(logrus.DebugLevel, msg, kv)
+}
+
+// Infow logs msg at info level with the alternating key/value pairs kv, e.g.
+// Infow("user created", "user_id", id, "admin", false).
+func Infow(msg string, kv ...interface{}) {
+	logw(logrus.InfoLevel, msg, kv)
+}
+
+// Warnw logs msg at warn level with the alternating key/value pairs kv.
+func Warnw(msg string, kv ...interface{}) {
+	logw(logrus.WarnLevel, msg, kv)
+}
+
+// Errorw logs msg at error level with the alternating key/value pairs kv.
+func Errorw(msg string, kv ...interface{}) {
+	logw(logrus.ErrorLevel, msg, kv)
+}
+
+// Fatalw logs msg at fatal level with the alternating key/value pairs kv,
+// then exits like logrus.Fatal.
+func Fatalw(msg string, kv ...interface{}) {
+	logw(logrus.FatalLevel, msg, kv)
+	GetLog().Exit(1)
+}
+
+// Panicw logs msg at panic level with the alternating key/value pairs kv,
+// then panics like logrus.Panic.
+func Panicw(msg string, kv ...interface{}) {
+	logw(logrus.PanicLevel, msg, kv)
+}
+
+// logw logs msg with the fields built from kv. Keys that are not strings are
+// formatted with fmt.Sprint; a trailing key without a value is reported in
+// the KVErrorKey field.
+func logw(level logrus.Level, msg string, kv []interface{}) {
+	if !GetLog().IsLevelEnabled(level) {
+		return
+	}
+
+	f := make(logrus.Fields, len(kv)/2+1)
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		f[key] = kv[i+1]
+	}
+	if len(kv)%2 != 0 {
+		f[KVErrorKey] = fmt.Sprintf("key %v has no value", kv[len(kv)-1])
+	}
+
+	entry().WithFields(f).Log(level, msg)
+}
@@ -0,0 +1,57 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"bytes"
+	"github.com/sirupsen/logrus"
+	"log/syslog"
+)
+
+// SetSyslogOutput sends the package logger output to syslog, with a priority
+// matching the level of each entry. An empty network and addr use the local
+// syslog socket. The formatted entry, JSON by default, is the message.
+func SetSyslogOutput(network, addr, tag string) error {
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return err
+	}
+
+	SetOutput(&syslogWriter{w: w})
+	return nil
+}
+
+type syslogWriter struct {
+	w     *syslog.Writer
+	level logrus.Level
+}
+
+func (w *syslogWriter) setLevel(level logrus.Level) {
+	w.level = level
+}
+
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimRight(p, "\n"))
+
+	var err error
+	switch w.level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		err = w.w.Crit(msg)
+	case logrus.ErrorLevel:
+		err = w.w.Err(msg)
+	case logrus.WarnLevel:
+		err = w.w.Warning(msg)
+	case logrus.InfoLevel:
+		err = w.w.Info(msg)
+	default:
+		err = w.w.Debug(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *syslogWriter) Close() error {
+	return w.w.Close()
+}
@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"errors"
+)
+
+// SetSyslogOutput is not supported on this platform.
+func SetSyslogOutput(network, addr, tag string) error {
+	return errors.New("logger: syslog is not supported on this platform")
+}
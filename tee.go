@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+)
+
+// SinkError is the error of one of the outputs set with SetOutputs, passed to
+// the handler set with SetErrorHandler. Use errors.As to tell which output
+// failed.
+type SinkError struct {
+	// Index is the position of the output in the SetOutputs arguments.
+	Index  int
+	Writer io.Writer
+	Err    error
+}
+
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("log output %d: %v", e.Index, e.Err)
+}
+
+func (e *SinkError) Unwrap() error {
+	return e.Err
+}
+
+// SetOutputs redirects the package logger to all of writers, written in the
+// given order. An output failing to write does not keep the others from
+// receiving the entry; the error of each failing output is reported on its
+// own as a *SinkError. Nil writers are skipped.
+func SetOutputs(writers ...io.Writer) {
+	ws := make([]io.Writer, 0, len(writers))
+	for _, w := range writers {
+		if w != nil {
+			ws = append(ws, w)
+		}
+	}
+
+	switch len(ws) {
+	case 0:
+		SetOutput(io.Discard)
+	case 1:
+		SetOutput(ws[0])
+	default:
+		SetOutput(&teeWriter{writers: ws})
+	}
+}
+
+type teeWriter struct {
+	writers []io.Writer
+}
+
+func (w *teeWriter) setLevel(level logrus.Level) {
+	for _, tw := range w.writers {
+		if lw, ok := tw.(leveledWriter); ok {
+			lw.setLevel(level)
+		}
+	}
+}
+
+// Write reports the errors of the outputs itself, so the entry counts as
+// written in Stats whatever they return.
+func (w *teeWriter) Write(p []byte) (int, error) {
+	for i, tw := range w.writers {
+		n, err := tw.Write(p)
+		if err == nil && n < len(p) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			reportWriteError(&SinkError{Index: i, Writer: tw, Err: err})
+		}
+	}
+	return len(p), nil
+}
+
+func (w *teeWriter) Close() error {
+	var errs []error
+	for _, tw := range w.writers {
+		errs = append(errs, closeWriter(tw))
+	}
+	return errors.Join(errs...)
+}
+
+func (w *teeWriter) Sync() error {
+	var errs []error
+	for _, tw := range w.writers {
+		errs = append(errs, syncWriter(tw))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// TruncatedSuffix ends the values shortened by SetMaxFieldLength and
+// SetMaxMessageLength.
+const TruncatedSuffix = "…(truncated)"
+
+// SetMaxFieldLength truncates the string field values of the package logger
+// longer than n characters to n characters followed by TruncatedSuffix. Zero
+// or less disables it. The values of the caller are left untouched, only
+// the written entry is shortened.
+func SetMaxFieldLength(n int) {
+	setTruncation(func(h *truncateHook) { h.field.Store(int64(n)) })
+}
+
+// SetMaxMessageLength truncates the messages of the package logger like
+// SetMaxFieldLength truncates field values.
+func SetMaxMessageLength(n int) {
+	setTruncation(func(h *truncateHook) { h.message.Store(int64(n)) })
+}
+
+// SetRecordTruncatedLength adds the original length in characters of every
+// truncated value in a sibling "<key>_length" field, "message_length" for
+// the message.
+func SetRecordTruncatedLength(enabled bool) {
+	setTruncation(func(h *truncateHook) { h.record.Store(enabled) })
+}
+
+// setTruncation updates the truncation hook, installing it the first time.
+// The hook does nothing while no limit is set.
+func setTruncation(update func(h *truncateHook)) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	p := stdPipeline()
+	h, ok := findHook[*truncateHook](p)
+	if !ok {
+		h = &truncateHook{}
+		p.addHook(h)
+	}
+	update(h)
+}
+
+type truncateHook struct {
+	field   atomic.Int64
+	message atomic.Int64
+	record  atomic.Bool
+}
+
+func (h *truncateHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *truncateHook) Fire(e *logrus.Entry) error {
+	record := h.record.Load()
+
+	if n := int(h.message.Load()); n > 0 {
+		if s, length, ok := truncate(e.Message, n); ok {
+			e.Message = s
+			if record {
+				e.Data["message_length"] = length
+			}
+		}
+	}
+
+	n := int(h.field.Load())
+	if n <= 0 {
+		return nil
+	}
+	for k, v := range e.Data {
+		str, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if s, length, ok := truncate(str, n); ok {
+			e.Data[k] = s
+			if record {
+				e.Data[k+"_length"] = length
+			}
+		}
+	}
+	return nil
+}
+
+// truncate shortens s to n characters followed by TruncatedSuffix, and
+// returns the length of s, when s is longer than n characters.
+func truncate(s string, n int) (string, int, bool) {
+	if len(s) <= n {
+		return s, 0, false
+	}
+	length := utf8.RuneCountInString(s)
+	if length <= n {
+		return s, 0, false
+	}
+
+	i := 0
+	for c := 0; c < n; c++ {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return s[:i] + TruncatedSuffix, length, true
+}
@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestTruncateAfterRedact(t *testing.T) {
+	_, r := Test(t)
+
+	SetMaxFieldLength(12)
+	t.Cleanup(func() { SetMaxFieldLength(0) })
+	AddRedactedPatterns(regexp.MustCompile(`\d{16}`))
+
+	WithField("card", "card 4111111111111111").Info("payment")
+
+	out := r.Output()
+	if strings.Contains(out, "4111") {
+		t.Fatalf("output %q leaks part of the card number", out)
+	}
+	if !strings.Contains(out, TruncatedSuffix) {
+		t.Fatalf("output %q, want the field truncated", out)
+	}
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"os"
+	"sort"
+)
+
+// FieldNameError is the error passed to the handler set with SetErrorHandler
+// for a field name rejected by the SetFieldNameValidator validator.
+type FieldNameError struct {
+	Key string
+	Err error
+}
+
+func (e *FieldNameError) Error() string {
+	return fmt.Sprintf("log field %q: %v", e.Key, e.Err)
+}
+
+func (e *FieldNameError) Unwrap() error {
+	return e.Err
+}
+
+// SetFieldNameValidator sets a function checking the field names of every
+// entry of the package logger before it is formatted, e.g. to enforce
+// snake_case or an allowlist in tests. Each rejected name is reported to the
+// error handler as a *FieldNameError, or on stderr when there is none; the
+// entry is still written. A nil validator removes it, and no checks run.
+func SetFieldNameValidator(validate func(key string) error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	p := stdPipeline()
+	if h, ok := findHook[*validateHook](p); ok {
+		p.removeHook(h)
+	}
+	if validate != nil {
+		p.addHook(&validateHook{validate: validate})
+	}
+}
+
+type validateHook struct {
+	validate func(key string) error
+}
+
+func (h *validateHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *validateHook) Fire(e *logrus.Entry) error {
+	keys := make([]string, 0, len(e.Data))
+	for k := range e.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := h.validate(k); err != nil {
+			if err = writeError(&FieldNameError{Key: k, Err: err}); err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid log field, %v\n", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"github.com/sirupsen/logrus"
+	"io"
+	golog "log"
+	"sync"
+)
+
+// Writer returns a writer logging every line written to it as an entry of
+// the package logger at level. Lines are logged once their newline is
+// written; empty lines are skipped.
+func Writer(level logrus.Level) io.Writer {
+	return &lineWriter{level: level}
+}
+
+// StdLogger returns a standard library logger writing through Writer, e.g.
+// for http.Server.ErrorLog.
+func StdLogger(level logrus.Level) *golog.Logger {
+	return golog.New(Writer(level), "", 0)
+}
+
+type lineWriter struct {
+	level logrus.Level
+	mu    sync.Mutex
+	buf   []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimRight(w.buf[:i], "\r")
+		if len(line) > 0 {
+			entry().Log(w.level, string(line))
+		}
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+
+	return len(p), nil
+}